@@ -2,20 +2,35 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	"github.com/rs/cors"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/accuracy"
 	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/compress"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/config"
 	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/handler"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/harmonic"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/history"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/metrics"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/push"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scheduler"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scoring"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/storage"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/webhook"
 )
 
 func main() {
@@ -24,39 +39,56 @@ func main() {
 		log.Println("Error loading .env file, using environment variables from OS")
 	}
 
-	// ロガーの初期化
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	// opts := &slog.HandlerOptions{
-	// 	Level: slog.LevelDebug, // ← DEBUGレベルのログも出力するように設定
-	// }
-	// logger := slog.New(slog.NewJSONHandler(os.Stdout, opts))
+	// ロガーの初期化（出力レベルは設定を読み込んだ後にLOG_LEVELの値にする）
+	var logLevel slog.LevelVar
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &logLevel}))
 
-	// 環境変数の読み込みと検証
-	adminPassword := os.Getenv("ADMIN_PASSWORD")
-	if adminPassword == "" {
+	// 設定の読み込みと検証（CONFIG_FILEを指定した場合はYAMLの設定ファイルも読み込み、環境変数の値を優先する）
+	cfg, err := config.Load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		var invalid *config.ValidationError
+		if errors.As(err, &invalid) {
+			for _, problem := range invalid.Problems {
+				logger.Error("設定が不正です", "problem", problem)
+			}
+		} else {
+			logger.Error("設定の読み込みエラー", "error", err)
+		}
+		os.Exit(1)
+	}
+	logLevel.Set(cfg.LogLevel())
+	if cfg.Auth.AdminPassword == "" {
 		logger.Warn("環境変数ADMIN_PASSWORDが設定されていません。管理人機能は無効になります。")
 	}
-	jwtSecret := os.Getenv("JWT_SECRET_KEY")
-	if jwtSecret == "" {
-		logger.Error("環境変数JWT_SECRET_KEYが設定されていません")
-		os.Exit(1)
+	if cfg.Auth.CronSecret == "" {
+		logger.Warn("環境変数CRON_SECRET_KEYが設定されていません。/api/tasks/ は使えません。")
 	}
-	jwtKey := []byte(jwtSecret)
 
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		logger.Error("環境変数DATABASE_URLが設定されていません")
-		os.Exit(1)
+	// データ取得元の選択（fixtureの場合は記録済みJSONを使いオフラインで動作する）
+	var providers provider.Set
+	switch cfg.Data.Source {
+	case "live":
+		providers = provider.NewHTTPSet(cfg.Data.PredictionAPIURL)
+	case "fixture":
+		logger.Info("フィクスチャのデータを使用します", "dir", cfg.Data.FixtureDir)
+		providers = provider.NewFixtureSet(cfg.Data.FixtureDir)
 	}
 
-	predictionURL := os.Getenv("PREDICTION_API_URL")
-	if predictionURL == "" {
-		logger.Error("環境変数PREDICTION_API_URLが設定されていません")
-		os.Exit(1)
+	// tide736.netが使えないときに使う調和定数（TIDE_STATIONS_PATHが未設定の場合は推算による代替を使わない）
+	if stationsPath := cfg.Data.TideStationsPath; stationsPath != "" {
+		table, err := harmonic.LoadTable(stationsPath)
+		if err != nil {
+			logger.Error("調和定数の読み込みエラー", "error", err)
+			os.Exit(1)
+		}
+		providers.TideFallback = provider.NewHarmonicTide(table)
+		logger.Info("調和定数を読み込みました", "path", stationsPath, "source", table.Source())
 	}
+	// データ取得元ごとの取得時間・失敗回数を記録する
+	providers = metrics.InstrumentProviders(providers)
 
 	// データベース接続の初期化
-	db, err := sql.Open("postgres", dbURL)
+	db, err := sql.Open("postgres", cfg.Database.URL)
 	if err != nil {
 		logger.Error("データベースのオープンエラー", "error", err)
 		os.Exit(1)
@@ -74,44 +106,159 @@ func main() {
 		os.Exit(1)
 	}
 	logger.Info("データベースに正常に接続しました！")
+	metrics.RegisterDB(db)
 
 	// キャッシュマネージャーの初期化と初回データ取得
-	cacheManager := cache.NewCacheManager(logger, predictionURL)
-	go cacheManager.FetchAndCachePredictionData()
-	go cacheManager.FetchAndCacheDetailData()
+	// 予測データは取得のたびにスナップショットとして保存する
+	historyStore := history.NewStore(db, logger)
+	cacheManager := cache.NewCacheManagerWithOptions(logger, providers, cfg.CacheOptions())
+	cacheManager.AddPredictionListener(historyStore.RecordPrediction)
+	metrics.RegisterCache(cacheManager)
+
+	// 前回のスナップショットを読み込み、初回取得が終わるまではそれを返す
+	switch cfg.Cache.Snapshot {
+	case "db":
+		cacheManager.SetSnapshotStore(cache.NewDBSnapshotStore(db))
+	case "file":
+		cacheManager.SetSnapshotStore(cache.NewFileSnapshotStore(cfg.Cache.SnapshotPath))
+	}
+	if err := cacheManager.LoadSnapshot(); err != nil {
+		logger.Warn("キャッシュのスナップショットを読み込めませんでした", "error", err)
+	}
+
+	// キャッシュの定期更新（起動時に初回更新を行う）
+	refreshScheduler := scheduler.New(logger, cacheManager, cfg.SchedulerConfig())
+	refreshScheduler.Start()
+
+	// 予報精度の夜間集計
+	accuracyScorer := accuracy.NewScorer(db, cfg.NightConfig(), logger)
+	accuracyScorer.StartNightlyJob()
+
+	// 爆湧き指数の設定（INDEX_CONFIG_PATHが未設定の場合は組み込みの設定を使う）
+	indexConfig := scoring.DefaultConfig()
+	if indexConfigPath := cfg.Data.IndexConfigPath; indexConfigPath != "" {
+		indexConfig, err = scoring.LoadConfig(indexConfigPath)
+		if err != nil {
+			logger.Error("爆湧き指数の設定エラー", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("爆湧き指数の設定を読み込みました", "path", indexConfigPath)
+	}
+	indexConfig.Night = cfg.NightConfig()
+	indexConfig.Season = cfg.Season()
+	indexCalculator := scoring.NewCalculator(cacheManager, indexConfig)
+
+	// Web Push通知（VAPID鍵が未設定の場合は無効、鍵の形式は設定の読み込み時に検証済み）
+	pushStore := push.NewStore(db)
+	vapidKeys, _ := cfg.VAPIDKeys()
+	var notifier *push.Notifier
+	if vapidKeys != nil {
+		notifier = push.NewNotifier(pushStore, push.NewSender(vapidKeys), cacheManager, cfg.NightConfig(), indexConfig.TimelineWeights(), indexConfig.TimelineWind(), cfg.Season(), logger)
+		cacheManager.AddPredictionListener(notifier.OnPrediction)
+	} else {
+		logger.Warn("環境変数VAPID_PUBLIC_KEY/VAPID_PRIVATE_KEYが設定されていません。プッシュ通知は無効になります。")
+	}
+
+	// 投稿画像の保存先（Supabase Storage、STORAGE_DISABLEDの場合は画像付きの投稿を受け付けない）
+	var imageStorage *storage.Supabase
+	if cfg.StorageEnabled() {
+		imageStorage = storage.NewSupabase(cfg.Storage.SupabaseURL, cfg.Storage.SupabaseServiceKey, logger)
+	} else {
+		logger.Info("STORAGE_DISABLEDが設定されているため、画像付きの投稿は受け付けません")
+	}
+
+	// 管理人が登録したWebhookへのイベント送信
+	webhookStore := webhook.NewStore(db)
+	webhookDispatcher := webhook.NewDispatcher(webhookStore, indexCalculator, cfg.Server.SiteURL, logger)
+	cacheManager.AddPredictionListener(webhookDispatcher.OnPrediction)
 
 	// HTTPハンドラの初期化
-	h := handler.NewHandler(db, logger, jwtKey, cacheManager)
+	h := handler.NewHandler(db, logger, cfg, cacheManager, refreshScheduler, historyStore, accuracyScorer, indexCalculator, pushStore, vapidKeys, imageStorage, webhookStore, webhookDispatcher)
 
 	// ルーターの設定
 	mux := http.NewServeMux()
 	h.RegisterRoutes(mux)
+	// Prometheusのメトリクス（METRICS_TOKENのBearerトークンが必要。未設定の場合は公開しない）
+	if cfg.Server.MetricsToken != "" {
+		mux.Handle("/metrics", metrics.Handler(cfg.Server.MetricsToken))
+	} else {
+		logger.Warn("環境変数METRICS_TOKENが設定されていません。/metricsは公開しません。")
+	}
 	mux.Handle("/uploads/", http.StripPrefix("/uploads/", http.FileServer(http.Dir("./uploads"))))
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "こんにちは、バックエンドです！")
 	})
 
 	// CORSの設定
-	allowedOriginsStr := os.Getenv("ALLOWED_ORIGINS")
-	var allowedOrigins []string
-	if allowedOriginsStr != "" {
-		allowedOrigins = strings.Split(allowedOriginsStr, ",")
-	} else {
-		allowedOrigins = []string{"http://localhost:3000", "http://localhost:3001", "https://bakuwaki-yoho.com"}
-	}
-	logger.Info("CORS AllowedOriginsを設定しました", "origins", allowedOrigins)
+	logger.Info("CORS AllowedOriginsを設定しました", "origins", cfg.Server.AllowedOrigins)
 
 	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:   allowedOrigins,
+		AllowedOrigins:   cfg.Server.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{"ETag", "Last-Modified", "X-Data-Age", "X-Data-Stale", "X-Request-ID"},
 		AllowCredentials: true,
-	}).Handler(mux)
+	}).Handler(compress.Middleware(mux))
 
 	// サーバーの起動
-	logger.Info("サーバーをポート8080で起動します...")
-	if err := http.ListenAndServe(":8080", corsHandler); err != nil {
-		logger.Error("サーバーの起動に失敗しました", "error", err)
-		os.Exit(1)
+	port := cfg.Server.Port
+	shutdownTimeout := cfg.Server.ShutdownTimeout
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           h.AccessLogMiddleware(metrics.Middleware(corsHandler)),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       60 * time.Second, // 画像付きの投稿を回線の遅い端末からも受け付けられるよう長めにする
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+	}
+
+	// SIGTERM（Cloud Runのインスタンス停止）・SIGINTを受けたら新しいリクエストの受け付けをやめて終了処理に入る
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("サーバーを起動します", "port", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("サーバーの起動に失敗しました", "error", err)
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+	}
+	stop()
+	logger.Info("停止シグナルを受け取りました。処理中のリクエストを待って終了します", "timeout", shutdownTimeout.String())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("処理中のリクエストを待ちきれませんでした", "error", err)
+	}
+	h.Close()
+	waitForShutdown(shutdownCtx, logger, "キャッシュ更新", refreshScheduler.Stop)
+	waitForShutdown(shutdownCtx, logger, "予報精度の夜間集計", accuracyScorer.Stop)
+	if notifier != nil {
+		waitForShutdown(shutdownCtx, logger, "プッシュ通知の送信", notifier.Wait)
+	}
+	waitForShutdown(shutdownCtx, logger, "Webhookの送信", webhookDispatcher.Wait)
+	logger.Info("サーバーを停止しました")
+}
+
+// waitForShutdown はwaitが返るまで待つ。ctxの期限が来た場合は待つのをやめる
+func waitForShutdown(ctx context.Context, logger *slog.Logger, name string, wait func()) {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Warn("終了を待ちきれませんでした", "target", name)
 	}
 }
@@ -0,0 +1,300 @@
+// backend/internal/accuracy/accuracy.go
+package accuracy
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+)
+
+// 現地情報を集計する時間帯は夜の時間帯（NIGHT_WINDOW）の前後を含める（既定の20時〜翌5時なら18時〜翌朝9時）
+// 掬いに行った人が翌朝に報告することも多いため、朝まで含める
+const (
+	reportLeadTime  = 2 * time.Hour
+	reportGraceTime = 4 * time.Hour
+)
+
+// 夜間ジョブで再集計する日数（遅れて付くリアクションや投票を反映するため）
+const nightlyRescoreDays = 3
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// Scorer は夜ごとの現地情報を集計し、予測値と突き合わせる
+type Scorer struct {
+	db     *sql.DB
+	night  night.Config
+	logger *slog.Logger
+
+	mu       sync.Mutex // 夜間ジョブと手動の再集計が同時に走らないようにする
+	stopMu   sync.Mutex // 停止後に新しい再集計を始めないようにする
+	stopOnce sync.Once
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewScorer は新しいScorerを初期化する
+func NewScorer(db *sql.DB, nightConfig night.Config, logger *slog.Logger) *Scorer {
+	return &Scorer{db: db, night: nightConfig, logger: logger, stop: make(chan struct{})}
+}
+
+// reportWindow は対象日の夜に対応する投稿の集計期間を返す
+func (s *Scorer) reportWindow(nightDate time.Time) (time.Time, time.Time) {
+	start, end := s.night.Window(nightDate)
+	return start.Add(-reportLeadTime), end.Add(reportGraceTime)
+}
+
+// ScoreNight は指定日の夜を集計し、night_observationsに保存する
+func (s *Scorer) ScoreNight(nightDate time.Time) (*model.NightObservation, error) {
+	start, end := s.reportWindow(nightDate)
+	obs := &model.NightObservation{NightDate: start.Format("2006-01-02")}
+
+	// 現地情報の投稿とリアクションを集計
+	rows, err := s.db.Query(`SELECT p.id, p.content,
+			COALESCE(SUM(CASE WHEN r.reaction_type = 'good' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN r.reaction_type = 'bad' THEN 1 ELSE 0 END), 0)
+		FROM posts p
+		LEFT JOIN reactions r ON r.post_id = p.id
+		WHERE p.label = '現地情報' AND p.created_at >= $1 AND p.created_at < $2
+		GROUP BY p.id, p.content`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("現地情報の集計クエリ失敗: %w", err)
+	}
+	var postIDs []int
+	var textScores []float64
+	for rows.Next() {
+		var id, good, bad int
+		var content string
+		if err := rows.Scan(&id, &content, &good, &bad); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("現地情報のスキャン失敗: %w", err)
+		}
+		postIDs = append(postIDs, id)
+		obs.GoodCount += good
+		obs.BadCount += bad
+		if score, ok := textIntensity(content); ok {
+			// 高評価の多い報告ほど信頼できるものとして重みを付ける
+			weight := math.Max(0.5, 1+float64(good)-0.5*float64(bad))
+			for i := 0; i < int(math.Round(weight)); i++ {
+				textScores = append(textScores, score)
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	obs.ReportCount = len(postIDs)
+	obs.TextScore = mean(textScores)
+
+	// 現地情報に付いたアンケートの結果を集計
+	if len(postIDs) > 0 {
+		optRows, err := s.db.Query(`SELECT po.option_text, po.vote_count
+			FROM poll_options po
+			JOIN polls p ON po.poll_id = p.id
+			WHERE p.post_id = ANY($1)`, pq.Array(postIDs))
+		if err != nil {
+			return nil, fmt.Errorf("アンケート集計クエリ失敗: %w", err)
+		}
+		var weighted float64
+		var votes int
+		for optRows.Next() {
+			var text string
+			var count int
+			if err := optRows.Scan(&text, &count); err != nil {
+				optRows.Close()
+				return nil, fmt.Errorf("アンケートのスキャン失敗: %w", err)
+			}
+			// 「閲覧用」など量を表さない選択肢は集計しない
+			if score, ok := textIntensity(text); ok && count > 0 {
+				weighted += score * float64(count)
+				votes += count
+			}
+		}
+		optRows.Close()
+		if err := optRows.Err(); err != nil {
+			return nil, err
+		}
+		obs.PollVotes = votes
+		if votes > 0 {
+			score := weighted / float64(votes)
+			obs.PollScore = &score
+		}
+	}
+	obs.ObservedScore = observedScore(obs)
+
+	// その夜が始まる前に取得された最新の予測値を使う
+	// 配列でないpayloadは、WHEREより先に展開されてもエラーにならないようCASEで空の配列にする
+	var predicted sql.NullFloat64
+	var fetchedAt sql.NullTime
+	err = s.db.QueryRow(`SELECT (elem->>'predicted_amount')::double precision, s.fetched_at
+		FROM prediction_snapshots s,
+			jsonb_array_elements(CASE WHEN jsonb_typeof(s.payload) = 'array' THEN s.payload ELSE '[]'::jsonb END) elem
+		WHERE elem->>'date' = $1 AND s.fetched_at <= $2
+		ORDER BY s.fetched_at DESC
+		LIMIT 1`, obs.NightDate, start).Scan(&predicted, &fetchedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("予測スナップショットの取得失敗: %w", err)
+	}
+	if predicted.Valid {
+		obs.PredictedAmount = &predicted.Float64
+	}
+	if fetchedAt.Valid {
+		obs.PredictionFetchedAt = &fetchedAt.Time
+	}
+
+	err = s.db.QueryRow(`INSERT INTO night_observations
+			(night_date, predicted_amount, prediction_fetched_at, report_count, good_count, bad_count, poll_votes, poll_score, text_score, observed_score, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP)
+		ON CONFLICT (night_date) DO UPDATE SET
+			predicted_amount = EXCLUDED.predicted_amount,
+			prediction_fetched_at = EXCLUDED.prediction_fetched_at,
+			report_count = EXCLUDED.report_count,
+			good_count = EXCLUDED.good_count,
+			bad_count = EXCLUDED.bad_count,
+			poll_votes = EXCLUDED.poll_votes,
+			poll_score = EXCLUDED.poll_score,
+			text_score = EXCLUDED.text_score,
+			observed_score = EXCLUDED.observed_score,
+			computed_at = EXCLUDED.computed_at
+		RETURNING computed_at`,
+		obs.NightDate, obs.PredictedAmount, obs.PredictionFetchedAt, obs.ReportCount, obs.GoodCount, obs.BadCount,
+		obs.PollVotes, obs.PollScore, obs.TextScore, obs.ObservedScore).Scan(&obs.ComputedAt)
+	if err != nil {
+		return nil, fmt.Errorf("集計結果の保存失敗: %w", err)
+	}
+	return obs, nil
+}
+
+// ScoreRecentNights は昨夜から遡ってdays日分の夜を再集計する
+// 他の再集計が実行中の場合は、終わるまで待ってから実行する
+func (s *Scorer) ScoreRecentNights(days int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().In(jst)
+	for i := 1; i <= days; i++ {
+		select {
+		case <-s.stop:
+			s.logger.Info("停止処理中のため夜間の精度集計を中断します")
+			return
+		default:
+		}
+		night := now.AddDate(0, 0, -i)
+		obs, err := s.ScoreNight(night)
+		if err != nil {
+			s.logger.Error("夜間の精度集計に失敗しました", "date", night.Format("2006-01-02"), "error", err)
+			continue
+		}
+		s.logger.Info("夜間の精度集計が完了しました", "date", obs.NightDate, "reports", obs.ReportCount)
+	}
+}
+
+// RescoreInBackground はdays日分の再集計をゴルーチンで実行する
+// 実行中の集計はStopで待たれる。停止処理中の場合は何もしない
+func (s *Scorer) RescoreInBackground(days int) {
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+	select {
+	case <-s.stop:
+		s.logger.Info("停止処理中のため夜間の精度集計を開始しません")
+		return
+	default:
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.ScoreRecentNights(days)
+	}()
+}
+
+// StartNightlyJob は毎日正午（JST）に直近の夜を再集計するゴルーチンを起動する
+func (s *Scorer) StartNightlyJob() {
+	s.wg.Add(1)
+	go s.nightlyLoop()
+}
+
+// Stop は夜間ジョブを止め、実行中の集計が終わるまで待つ
+func (s *Scorer) Stop() {
+	s.stopMu.Lock()
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.stopMu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Scorer) nightlyLoop() {
+	defer s.wg.Done()
+	for {
+		now := time.Now().In(jst)
+		next := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, jst)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			s.ScoreRecentNights(nightlyRescoreDays)
+		case <-s.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// GetAccuracy は期間内の集計結果と精度指標を返す
+func (s *Scorer) GetAccuracy(from, to string) (*model.AccuracyResponse, error) {
+	rows, err := s.db.Query(`SELECT night_date, predicted_amount, prediction_fetched_at, report_count, good_count, bad_count,
+			poll_votes, poll_score, text_score, observed_score, computed_at
+		FROM night_observations
+		WHERE night_date >= $1 AND night_date <= $2
+		ORDER BY night_date ASC`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("精度データのクエリ失敗: %w", err)
+	}
+	defer rows.Close()
+
+	nights := []model.NightObservation{}
+	for rows.Next() {
+		var obs model.NightObservation
+		var nightDate time.Time
+		var predicted, pollScore, textScore, observed sql.NullFloat64
+		var fetchedAt sql.NullTime
+		if err := rows.Scan(&nightDate, &predicted, &fetchedAt, &obs.ReportCount, &obs.GoodCount, &obs.BadCount,
+			&obs.PollVotes, &pollScore, &textScore, &observed, &obs.ComputedAt); err != nil {
+			s.logger.Error("精度データのスキャンエラー", "error", err)
+			continue
+		}
+		obs.NightDate = nightDate.Format("2006-01-02")
+		obs.PredictedAmount = nullFloatPtr(predicted)
+		obs.PollScore = nullFloatPtr(pollScore)
+		obs.TextScore = nullFloatPtr(textScore)
+		obs.ObservedScore = nullFloatPtr(observed)
+		if fetchedAt.Valid {
+			obs.PredictionFetchedAt = &fetchedAt.Time
+		}
+		nights = append(nights, obs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &model.AccuracyResponse{
+		From:    from,
+		To:      to,
+		Metrics: ComputeMetrics(nights),
+		Nights:  nights,
+	}, nil
+}
+
+func nullFloatPtr(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	f := v.Float64
+	return &f
+}
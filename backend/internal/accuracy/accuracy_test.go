@@ -0,0 +1,56 @@
+// backend/internal/accuracy/accuracy_test.go
+package accuracy
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+)
+
+// TestStopNightlyJob は夜間ジョブが次の実行時刻を待たずに止まることをテストする
+func TestStopNightlyJob(t *testing.T) {
+	s := NewScorer(nil, night.DefaultConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.StartNightlyJob()
+
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		s.Stop() // 2回呼んでも問題ない
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stopが夜間ジョブの終了を待ったまま戻りません")
+	}
+}
+
+// TestRescoreInBackgroundAfterStop は停止後に手動の再集計を始めないことをテストする
+func TestRescoreInBackgroundAfterStop(t *testing.T) {
+	// dbがnilなので、集計が始まるとパニックになる
+	s := NewScorer(nil, night.DefaultConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.Stop()
+	s.RescoreInBackground(3)
+	s.Stop()
+}
+
+// TestReportWindow は夜の時間帯の前後を含めて現地情報を集計することをテストする
+func TestReportWindow(t *testing.T) {
+	date := time.Date(2026, 4, 10, 0, 0, 0, 0, jst)
+	tests := []struct {
+		night      night.Config
+		start, end time.Time
+	}{
+		{night.DefaultConfig(), time.Date(2026, 4, 10, 18, 0, 0, 0, jst), time.Date(2026, 4, 11, 9, 0, 0, 0, jst)},
+		{night.Config{StartHour: 22, EndHour: 3}, time.Date(2026, 4, 10, 20, 0, 0, 0, jst), time.Date(2026, 4, 11, 7, 0, 0, 0, jst)},
+	}
+	for _, tt := range tests {
+		s := NewScorer(nil, tt.night, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		if start, end := s.reportWindow(date); !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("reportWindow(%+v) = %v〜%v, want %v〜%v", tt.night, start, end, tt.start, tt.end)
+		}
+	}
+}
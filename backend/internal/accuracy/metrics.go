@@ -0,0 +1,255 @@
+// backend/internal/accuracy/metrics.go
+package accuracy
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// 予測値の正規化に使う「爆湧き」の閾値と、「湧いた」とみなす予測値の閾値（「湧き」以上）
+// レベルの境界を変えても揃うよう、levelパッケージの閾値から取る
+var (
+	bakuwakiThreshold     = level.Threshold(5) // 爆湧き
+	predictedHitThreshold = level.Threshold(3) // 湧き
+)
+
+// 「湧いた」とみなす観測スコアの閾値（0〜1の中間以上）
+const observedHitThreshold = 0.5
+
+// 現地情報の文面やアンケートの選択肢から量を読み取るためのキーワード
+// 上から順に評価し、最初に一致したものを採用する
+// 直後に否定が続く場合（「わきなし」「湧いてない」など）はnegatedのスコアを使い、nilの場合はその一致を使わない
+var intensityKeywords = []struct {
+	words   []string
+	score   float64
+	negated *float64
+}{
+	{[]string{"ゼロ", "ボウズ", "坊主", "一匹も"}, 0.0, nil},
+	{[]string{"爆湧き", "爆わき", "大量", "めちゃくちゃ", "すごい数"}, 1.0, nil},
+	{[]string{"大湧き", "たくさん", "多い", "多め", "結構"}, 0.8, nil},
+	{[]string{"湧き", "わき", "湧い", "そこそこ", "まあまあ"}, 0.6, &zeroIntensity},
+	{[]string{"少ない", "少し", "ちらほら", "ぽつぽつ", "数匹", "チョイ", "プチ"}, 0.3, nil},
+}
+
+var zeroIntensity = 0.0
+
+// 「10匹」のような数で書かれた量（全角数字も含む）
+var countPattern = regexp.MustCompile(`[0-9０-９]+\s*匹`)
+
+// 数で書かれた量の区分（min匹以上でscore）
+var countLevels = []struct {
+	min   int
+	score float64
+}{
+	{100, 1.0},
+	{50, 0.8},
+	{10, 0.6},
+	{1, 0.3},
+	{0, 0.0},
+}
+
+// キーワードと否定の間に入る助詞など（「湧いていない」「湧きはなかった」）
+var negationFillers = []string{"て", "で", "は", "も", "い", "じゃ"}
+
+// 否定の表現
+var negationWords = []string{"ない", "なし", "無し", "なかった", "ません", "ず"}
+
+// textIntensity はテキストから身投げ量を0〜1で推定する
+// 「10匹」のように数で書かれていればそれを優先し、なければキーワードから推定する
+// 該当するキーワードがない場合はfalseを返す
+func textIntensity(text string) (float64, bool) {
+	for _, loc := range countPattern.FindAllStringIndex(text, -1) {
+		digits := strings.TrimRightFunc(strings.TrimSuffix(text[loc[0]:loc[1]], "匹"), unicode.IsSpace)
+		n, err := strconv.Atoi(strings.Map(narrowDigit, digits))
+		if err != nil {
+			continue
+		}
+		if n > 0 && negatedAfter(text[loc[1]:]) {
+			// 「1匹もいなかった」
+			return 0, true
+		}
+		for _, l := range countLevels {
+			if n >= l.min {
+				return l.score, true
+			}
+		}
+	}
+
+	// 長いキーワードの一部として一致した短いキーワード（「爆湧き」の中の「湧き」など）は使わない
+	var matched [][2]int
+	inMatched := func(start, end int) bool {
+		for _, m := range matched {
+			if start >= m[0] && end <= m[1] {
+				return true
+			}
+		}
+		return false
+	}
+	for _, k := range intensityKeywords {
+		for _, w := range k.words {
+			for offset := 0; ; {
+				i := strings.Index(text[offset:], w)
+				if i < 0 {
+					break
+				}
+				start, end := offset+i, offset+i+len(w)
+				offset = end
+				if inMatched(start, end) {
+					continue
+				}
+				matched = append(matched, [2]int{start, end})
+				if !negatedAfter(text[end:]) {
+					return k.score, true
+				}
+				if k.negated != nil {
+					return *k.negated, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// narrowDigit は全角数字を半角にする
+func narrowDigit(r rune) rune {
+	if r >= '０' && r <= '９' {
+		return r - '０' + '0'
+	}
+	return r
+}
+
+// negatedAfter はキーワードの直後が否定の表現かを返す
+func negatedAfter(rest string) bool {
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, f := range negationFillers {
+			if strings.HasPrefix(rest, f) {
+				rest = rest[len(f):]
+				trimmed = true
+			}
+		}
+	}
+	for _, n := range negationWords {
+		if strings.HasPrefix(rest, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// observedScore は投稿数・文面・アンケートから0〜1の観測スコアを算出する
+// 報告が1件もない夜は「行った人がいない」可能性があるため観測なしとする
+func observedScore(obs *model.NightObservation) *float64 {
+	if obs.ReportCount == 0 {
+		return nil
+	}
+	// 報告の多さ（高評価を半分の重みで加算）。20件相当で1.0になる
+	activity := math.Min(1, math.Log1p(float64(obs.ReportCount)+0.5*float64(obs.GoodCount))/math.Log1p(20))
+
+	sum, weights := 0.4*activity, 0.4
+	if obs.TextScore != nil {
+		sum += 0.3 * *obs.TextScore
+		weights += 0.3
+	}
+	if obs.PollScore != nil {
+		sum += 0.3 * *obs.PollScore
+		weights += 0.3
+	}
+	score := sum / weights
+	return &score
+}
+
+// ComputeMetrics は予測値と観測スコアの両方がある夜について精度指標を算出する
+func ComputeMetrics(nights []model.NightObservation) model.AccuracyMetrics {
+	var predicted, observed []float64
+	for _, n := range nights {
+		if n.PredictedAmount == nil || n.ObservedScore == nil {
+			continue
+		}
+		predicted = append(predicted, *n.PredictedAmount)
+		observed = append(observed, *n.ObservedScore)
+	}
+
+	metrics := model.AccuracyMetrics{Nights: len(predicted)}
+	if len(predicted) == 0 {
+		return metrics
+	}
+
+	hits := 0
+	var biasSum float64
+	for i := range predicted {
+		if (predicted[i] >= predictedHitThreshold) == (observed[i] >= observedHitThreshold) {
+			hits++
+		}
+		biasSum += math.Min(predicted[i]/bakuwakiThreshold, 1) - observed[i]
+	}
+	hitRate := float64(hits) / float64(len(predicted))
+	bias := biasSum / float64(len(predicted))
+	metrics.HitRate = &hitRate
+	metrics.Bias = &bias
+	metrics.RankCorrelation = spearman(predicted, observed)
+	return metrics
+}
+
+// spearman はスピアマンの順位相関係数を返す（3件未満または分散がない場合はnil）
+func spearman(x, y []float64) *float64 {
+	if len(x) < 3 || len(x) != len(y) {
+		return nil
+	}
+	rx, ry := ranks(x), ranks(y)
+	mx, my := *mean(rx), *mean(ry)
+	var cov, vx, vy float64
+	for i := range rx {
+		dx, dy := rx[i]-mx, ry[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return nil
+	}
+	rho := cov / math.Sqrt(vx*vy)
+	return &rho
+}
+
+// ranks は同順位を平均順位として扱った順位を返す
+func ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+
+	result := make([]float64, len(values))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[idx[k]] = avg
+		}
+		i = j + 1
+	}
+	return result
+}
+
+func mean(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	m := sum / float64(len(values))
+	return &m
+}
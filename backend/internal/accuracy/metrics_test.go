@@ -0,0 +1,87 @@
+// backend/internal/accuracy/metrics_test.go
+package accuracy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+// TestTextIntensity は文面から身投げ量を推定できることをテストする
+func TestTextIntensity(t *testing.T) {
+	cases := []struct {
+		text  string
+		score float64
+		ok    bool
+	}{
+		{"滑川で爆湧きしてました！", 1.0, true},
+		{"湧きなしでした", 0.0, true},
+		{"ちらほらいました", 0.3, true},
+		{"閲覧用", 0, false},
+		// 数は数字全体で読む（「10匹」の中の「0匹」に一致させない）
+		{"10匹くらい", 0.6, true},
+		{"0匹でした", 0.0, true},
+		{"１２０匹すくえた", 1.0, true},
+		{"3 匹だけ", 0.3, true},
+		{"1匹もいなかった", 0.0, true},
+		{"10匹もいた", 0.6, true},
+		// キーワードの直後の否定
+		{"わきなし", 0.0, true},
+		{"全然湧いてない", 0.0, true},
+		{"湧いていませんでした", 0.0, true},
+		{"湧きはなかった", 0.0, true},
+		{"湧いてました", 0.6, true},
+		{"爆湧きではなかったけど、そこそこ", 0.6, true},
+		{"爆湧きではなかった", 0, false},
+		// 量と関係のない「いない」「わい」には一致させない
+		{"人がいないので穴場", 0, false},
+		{"イカがかわいい", 0, false},
+	}
+	for _, c := range cases {
+		score, ok := textIntensity(c.text)
+		if ok != c.ok || score != c.score {
+			t.Errorf("textIntensity(%q) = (%v, %v), want (%v, %v)", c.text, score, ok, c.score, c.ok)
+		}
+	}
+}
+
+// TestComputeMetrics は予測と観測が揃った夜だけで指標を算出することをテストする
+func TestComputeMetrics(t *testing.T) {
+	nights := []model.NightObservation{
+		{NightDate: "2026-04-01", PredictedAmount: ptr(0.2), ObservedScore: ptr(0.1)},
+		{NightDate: "2026-04-02", PredictedAmount: ptr(0.7), ObservedScore: ptr(0.4)},
+		{NightDate: "2026-04-03", PredictedAmount: ptr(1.4), ObservedScore: ptr(0.9)},
+		{NightDate: "2026-04-04", PredictedAmount: ptr(1.0), ObservedScore: nil}, // 報告なし
+		{NightDate: "2026-04-05", PredictedAmount: nil, ObservedScore: ptr(0.8)}, // 予測なし
+	}
+
+	m := ComputeMetrics(nights)
+	if m.Nights != 3 {
+		t.Fatalf("Nights = %d, want 3", m.Nights)
+	}
+	if m.HitRate == nil || *m.HitRate != 1 {
+		t.Errorf("HitRate = %v, want 1", m.HitRate)
+	}
+	if m.RankCorrelation == nil || math.Abs(*m.RankCorrelation-1) > 1e-9 {
+		t.Errorf("RankCorrelation = %v, want 1", m.RankCorrelation)
+	}
+	// (0.2/1.4-0.1 + 0.7/1.4-0.4 + 1.0-0.9) / 3
+	wantBias := (0.2/1.4 - 0.1 + 0.5 - 0.4 + 0.1) / 3
+	if m.Bias == nil || math.Abs(*m.Bias-wantBias) > 1e-9 {
+		t.Errorf("Bias = %v, want %v", m.Bias, wantBias)
+	}
+}
+
+// TestRanksWithTies は同順位が平均順位になることをテストする
+func TestRanksWithTies(t *testing.T) {
+	got := ranks([]float64{3, 1, 3, 2})
+	want := []float64{3.5, 1, 3.5, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ranks = %v, want %v", got, want)
+		}
+	}
+}
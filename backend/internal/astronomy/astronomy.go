@@ -0,0 +1,207 @@
+// backend/internal/astronomy/astronomy.go
+package astronomy
+
+import (
+	"math"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// 朔望月（日）
+const SynodicMonth = 29.530588853
+
+// 出没とみなす高度（度）。太陽は大気差と視半径を考慮した値
+const sunriseAltitude = -0.833
+
+// 出没時刻を探すときの刻み幅（この間は高度を線形補間する）
+const scanStep = 10 * time.Minute
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// Day は指定地点・指定日（JST）の月齢・月の出入り・日の出入りを計算する
+// 月齢と輝面比は正午（JST）時点の値
+func Day(latitude, longitude float64, date time.Time) model.AstronomyDay {
+	d := date.In(jst)
+	start := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, jst)
+	noon := start.Add(12 * time.Hour)
+
+	age := MoonAge(noon)
+	day := model.AstronomyDay{
+		Date:             start.Format("2006-01-02"),
+		MoonAge:          math.Round(age*10) / 10,
+		MoonPhase:        PhaseName(age),
+		MoonIllumination: math.Round(Illumination(noon)*10) / 10,
+	}
+	day.Sunrise, day.Sunset = riseSet(start, latitude, longitude, func(t time.Time) (float64, float64, float64) {
+		ra, dec := sunPosition(julianDay(t))
+		return ra, dec, sunriseAltitude
+	})
+	day.Moonrise, day.Moonset = riseSet(start, latitude, longitude, func(t time.Time) (float64, float64, float64) {
+		ra, dec, parallax := moonPosition(julianDay(t))
+		// 地心高度で判定するため、視差・大気差・視半径をまとめて補正する（Meeus）
+		return ra, dec, 0.7275*parallax - 0.5667
+	})
+	return day
+}
+
+// MoonAge は指定時刻の月齢（直前の新月からの経過日数）を返す
+func MoonAge(t time.Time) float64 {
+	jd := julianDay(t)
+	k := math.Floor((jd - 2451550.09766) / SynodicMonth)
+	for newMoon(k) > jd {
+		k--
+	}
+	for newMoon(k+1) <= jd {
+		k++
+	}
+	return jd - newMoon(k)
+}
+
+// PhaseName は月齢から月相の名前を返す
+func PhaseName(age float64) string {
+	names := []string{"新月", "三日月", "上弦の月", "十三夜月", "満月", "寝待月", "下弦の月", "有明月"}
+	i := int(math.Floor(age/SynodicMonth*8+0.5)) % 8
+	return names[i]
+}
+
+// Illumination は指定時刻の月の輝面比（%）を返す
+func Illumination(t time.Time) float64 {
+	jd := julianDay(t)
+	sunLon := sunLongitude(jd)
+	moonLon, moonLat, _ := moonEcliptic(jd)
+	cosElongation := math.Cos(rad(moonLat)) * math.Cos(rad(moonLon-sunLon))
+	return (1 - cosElongation) / 2 * 100
+}
+
+// SunAltitude は指定時刻・地点の太陽の高度（度）を返す
+func SunAltitude(latitude, longitude float64, t time.Time) float64 {
+	jd := julianDay(t)
+	ra, dec := sunPosition(jd)
+	return altitude(jd, latitude, longitude, ra, dec)
+}
+
+// MoonAltitude は指定時刻・地点の月の高度（度、視差を補正した地表からの高度）を返す
+func MoonAltitude(latitude, longitude float64, t time.Time) float64 {
+	jd := julianDay(t)
+	ra, dec, parallax := moonPosition(jd)
+	h := altitude(jd, latitude, longitude, ra, dec)
+	return h - parallax*math.Cos(rad(h))
+}
+
+// riseSet は指定日0時から24時間の間で天体の高度が出没高度をまたぐ時刻を探す
+// その日に出（または入り）がない場合はnilを返す
+func riseSet(start time.Time, latitude, longitude float64, position func(time.Time) (ra, dec, h0 float64)) (rise, set *time.Time) {
+	height := func(t time.Time) float64 {
+		ra, dec, h0 := position(t)
+		return altitude(julianDay(t), latitude, longitude, ra, dec) - h0
+	}
+	end := start.Add(24 * time.Hour)
+	prevTime, prev := start, height(start)
+	for t := start.Add(scanStep); !t.After(end); t = t.Add(scanStep) {
+		cur := height(t)
+		if (prev < 0) != (cur < 0) {
+			frac := prev / (prev - cur)
+			crossing := prevTime.Add(time.Duration(frac * float64(scanStep))).Truncate(time.Minute)
+			if crossing.Before(end) {
+				if prev < 0 && rise == nil {
+					rise = &crossing
+				} else if prev >= 0 && set == nil {
+					set = &crossing
+				}
+			}
+		}
+		prevTime, prev = t, cur
+	}
+	return rise, set
+}
+
+// altitude は赤経・赤緯（度）の天体の地平高度（度）を返す
+func altitude(jd, latitude, longitude, ra, dec float64) float64 {
+	d := jd - 2451545.0
+	gmst := 280.46061837 + 360.98564736629*d
+	hourAngle := gmst + longitude - ra
+	sinAlt := math.Sin(rad(latitude))*math.Sin(rad(dec)) + math.Cos(rad(latitude))*math.Cos(rad(dec))*math.Cos(rad(hourAngle))
+	return deg(math.Asin(sinAlt))
+}
+
+// sunLongitude は太陽の視黄経（度）を返す（精度0.01度程度）
+func sunLongitude(jd float64) float64 {
+	d := jd - 2451545.0
+	l := 280.460 + 0.9856474*d
+	g := rad(357.528 + 0.9856003*d)
+	return l + 1.915*math.Sin(g) + 0.020*math.Sin(2*g)
+}
+
+// sunPosition は太陽の赤経・赤緯（度）を返す
+func sunPosition(jd float64) (float64, float64) {
+	return eclipticToEquatorial(jd, sunLongitude(jd), 0)
+}
+
+// moonEcliptic は月の黄経・黄緯・地平視差（度）を返す（精度0.3度程度）
+func moonEcliptic(jd float64) (lon, lat, parallax float64) {
+	t := (jd - 2451545.0) / 36525
+	sin := func(a, b float64) float64 { return math.Sin(rad(a + b*t)) }
+	cos := func(a, b float64) float64 { return math.Cos(rad(a + b*t)) }
+	lon = 218.32 + 481267.881*t +
+		6.29*sin(135.0, 477198.87) - 1.27*sin(259.3, -413335.36) +
+		0.66*sin(235.7, 890534.22) + 0.21*sin(269.9, 954397.74) -
+		0.19*sin(357.5, 35999.05) - 0.11*sin(186.5, 966404.03)
+	lat = 5.13*sin(93.3, 483202.02) + 0.28*sin(228.2, 960400.89) -
+		0.28*sin(318.3, 6003.15) - 0.17*sin(217.6, -407332.21)
+	parallax = 0.9508 + 0.0518*cos(135.0, 477198.87) + 0.0095*cos(259.3, -413335.36) +
+		0.0078*cos(235.7, 890534.22) + 0.0028*cos(269.9, 954397.74)
+	return lon, lat, parallax
+}
+
+// moonPosition は月の赤経・赤緯・地平視差（度）を返す
+func moonPosition(jd float64) (float64, float64, float64) {
+	lon, lat, parallax := moonEcliptic(jd)
+	ra, dec := eclipticToEquatorial(jd, lon, lat)
+	return ra, dec, parallax
+}
+
+func eclipticToEquatorial(jd, lon, lat float64) (ra, dec float64) {
+	eps := rad(23.439 - 0.0000004*(jd-2451545.0))
+	l, b := rad(lon), rad(lat)
+	x := math.Cos(b) * math.Cos(l)
+	y := math.Cos(eps)*math.Cos(b)*math.Sin(l) - math.Sin(eps)*math.Sin(b)
+	z := math.Sin(eps)*math.Cos(b)*math.Sin(l) + math.Cos(eps)*math.Sin(b)
+	return deg(math.Atan2(y, x)), deg(math.Asin(z))
+}
+
+// newMoon はk番目（2000年1月6日を0とする）の新月のユリウス日を返す（Meeus 第49章、主要項のみ）
+func newMoon(k float64) float64 {
+	t := k / 1236.85
+	jde := 2451550.09766 + SynodicMonth*k + 0.00015437*t*t
+	e := 1 - 0.002516*t - 0.0000074*t*t
+	m := rad(2.5534 + 29.10535670*k - 0.0000014*t*t)
+	mp := rad(201.5643 + 385.81693528*k + 0.0107582*t*t)
+	f := rad(160.7108 + 390.67050284*k - 0.0016118*t*t)
+	omega := rad(124.7746 - 1.56375588*k + 0.0020672*t*t)
+	jde += -0.40720*math.Sin(mp) +
+		0.17241*e*math.Sin(m) +
+		0.01608*math.Sin(2*mp) +
+		0.01039*math.Sin(2*f) +
+		0.00739*e*math.Sin(mp-m) -
+		0.00514*e*math.Sin(mp+m) +
+		0.00208*e*e*math.Sin(2*m) -
+		0.00111*math.Sin(mp-2*f) -
+		0.00057*math.Sin(mp+2*f) +
+		0.00056*e*math.Sin(2*mp+m) -
+		0.00042*math.Sin(3*mp) +
+		0.00042*e*math.Sin(m+2*f) +
+		0.00038*e*math.Sin(m-2*f) -
+		0.00024*e*math.Sin(2*mp-m) -
+		0.00017*math.Sin(omega)
+	// 力学時から世界時へ（ΔT ≒ 69秒）
+	return jde - 69.0/86400
+}
+
+func julianDay(t time.Time) float64 {
+	return float64(t.UnixMilli())/86400000 + 2440587.5
+}
+
+func rad(d float64) float64 { return d * math.Pi / 180 }
+
+func deg(r float64) float64 { return r * 180 / math.Pi }
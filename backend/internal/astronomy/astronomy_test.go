@@ -0,0 +1,59 @@
+// backend/internal/astronomy/astronomy_test.go
+package astronomy
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// 東京（国立天文台の暦計算室の値と比較する）
+const tokyoLat, tokyoLon = 35.6581, 139.7414
+
+func within(t *testing.T, name string, got *time.Time, want string, tolerance time.Duration) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s: 時刻がnilです", name)
+		return
+	}
+	w, _ := time.ParseInLocation("2006-01-02 15:04", want, jst)
+	if diff := got.Sub(w); diff > tolerance || diff < -tolerance {
+		t.Errorf("%s = %s, want %s", name, got.Format("15:04"), want)
+	}
+}
+
+// TestSunTimes は日の出・日の入りが暦の値と数分以内で一致することをテストする
+func TestSunTimes(t *testing.T) {
+	day := Day(tokyoLat, tokyoLon, time.Date(2024, 6, 21, 0, 0, 0, 0, jst))
+	within(t, "日の出", day.Sunrise, "2024-06-21 04:25", 3*time.Minute)
+	within(t, "日の入り", day.Sunset, "2024-06-21 19:00", 3*time.Minute)
+
+	day = Day(tokyoLat, tokyoLon, time.Date(2024, 12, 22, 0, 0, 0, 0, jst))
+	within(t, "日の出", day.Sunrise, "2024-12-22 06:47", 3*time.Minute)
+	within(t, "日の入り", day.Sunset, "2024-12-22 16:32", 3*time.Minute)
+}
+
+// TestMoonAge は新月・満月の時刻付近で月齢と月相が正しいことをテストする
+func TestMoonAge(t *testing.T) {
+	// 2024年1月11日 20:57 JST が新月
+	newMoonTime := time.Date(2024, 1, 11, 20, 57, 0, 0, jst)
+	if age := MoonAge(newMoonTime.Add(time.Hour)); age > 0.1 {
+		t.Errorf("新月直後の月齢 = %v, want 0付近", age)
+	}
+	if age := MoonAge(newMoonTime.Add(-time.Hour)); age < SynodicMonth-0.5 {
+		t.Errorf("新月直前の月齢 = %v, want 29.5付近", age)
+	}
+
+	// 2024年1月26日 02:54 JST が満月
+	fullMoon := Day(tokyoLat, tokyoLon, time.Date(2024, 1, 25, 0, 0, 0, 0, jst))
+	if math.Abs(fullMoon.MoonAge-13.6) > 0.3 || fullMoon.MoonPhase != "満月" || fullMoon.MoonIllumination < 95 {
+		t.Errorf("満月の日の月齢・月相・輝面比が不正です: %+v", fullMoon)
+	}
+	// 満月の日は日の入り頃に月が出て、日の出頃に沈む
+	if fullMoon.Moonrise == nil || fullMoon.Moonrise.Sub(*fullMoon.Sunset).Abs() > 90*time.Minute {
+		t.Errorf("満月の月の出が日の入りから離れすぎています: %v, %v", fullMoon.Moonrise, fullMoon.Sunset)
+	}
+	if fullMoon.Moonset == nil || fullMoon.Moonset.Sub(*fullMoon.Sunrise).Abs() > 90*time.Minute {
+		t.Errorf("満月の月の入りが日の出から離れすぎています: %v, %v", fullMoon.Moonset, fullMoon.Sunrise)
+	}
+}
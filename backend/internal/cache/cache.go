@@ -2,182 +2,504 @@
 package cache
 
 import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"sync"
 	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/compress"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
 )
 
-// CacheManager は予測データと詳細データのキャッシュを管理
+// CacheManager は予測データと地点ごとの詳細データのキャッシュを管理
 type CacheManager struct {
-	logger        *slog.Logger
-	predictionURL string
+	logger          *slog.Logger
+	providers       provider.Set
+	spots           []spot.Spot
+	requestInterval time.Duration // 外部APIへの負荷軽減のためのリクエスト間隔
+	diffHistory     int           // 保持する予測データの差分の件数
 	predictionCache struct {
 		sync.RWMutex
-		data []byte
+		entry *Entry
 	}
 	detailCache struct {
 		sync.RWMutex
-		data map[string][]byte
+		data  map[string]*Entry       // 外部APIのレスポンスをそのまままとめた形式（schema=1）
+		typed map[string]*typedDetail // 型付きの形式（schema=2）
+	}
+	predictionDiffs struct {
+		sync.RWMutex
+		items []model.PredictionDiff // 古い順
+	}
+	// tide736.netから取得した日付ごとの潮汐データ（潮汐は日付が決まれば変わらないので更新をまたいで使う）
+	// computedはtide736.netが使えなかった日付の推算値。次の更新でもtide736.netを試し、取得できたら置き換える
+	tideCache struct {
+		sync.RWMutex
+		data     map[string]map[string]interface{}
+		computed map[string]map[string]interface{}
+	}
+	// 外部APIへのリクエストの間隔をrequestInterval以上空けるための、前回のリクエストの時刻
+	throttle struct {
+		sync.Mutex
+		last time.Time
 	}
+	listenersMu         sync.RWMutex
+	predictionListeners []PredictionListener
+	snapshotStore       SnapshotStore
+}
+
+// Entry はキャッシュされたデータと、その取得時刻・ハッシュ
+type Entry struct {
+	Data      []byte
+	FetchedAt time.Time
+	Hash      string // DataのSHA-256（16進数）
+	Stale     bool   // スナップショットから復元し、まだ再取得できていないデータ
+	// リクエストごとに圧縮しなくて済むよう、キャッシュ時に圧縮しておいたData（圧縮に失敗した場合はnil）
+	Gzip   []byte
+	Brotli []byte
 }
 
-// NewCacheManager は新しいCacheManagerを初期化する
+// キャッシュ時の圧縮レベル（更新時に1回だけ圧縮するので高めにする）
+const (
+	entryGzipLevel   = gzip.BestCompression
+	entryBrotliLevel = 9
+)
+
+// ETag はHTTPレスポンス用の強いETagを返す
+func (e *Entry) ETag() string {
+	return `"` + e.Hash[:32] + `"`
+}
+
+// Encoded は指定した圧縮形式のデータとそのETagを返す
+// 圧縮済みのデータがない形式の場合はokがfalseになる
+func (e *Entry) Encoded(encoding string) (data []byte, etag string, ok bool) {
+	switch encoding {
+	case compress.Brotli:
+		data = e.Brotli
+	case compress.Gzip:
+		data = e.Gzip
+	}
+	if data == nil {
+		return nil, "", false
+	}
+	// 表現ごとに異なる強いETagにする
+	return data, `"` + e.Hash[:32] + "-" + encoding + `"`, true
+}
+
+func newEntry(data []byte, fetchedAt time.Time) *Entry {
+	hash := sha256.Sum256(data)
+	entry := &Entry{Data: data, FetchedAt: fetchedAt, Hash: hex.EncodeToString(hash[:])}
+	entry.Gzip, _ = compress.GzipBytes(data, entryGzipLevel)
+	entry.Brotli, _ = compress.BrotliBytes(data, entryBrotliLevel)
+	return entry
+}
+
+// typedDetail は型付きの詳細データと、そのJSONのキャッシュエントリ
+type typedDetail struct {
+	data  *model.DetailData
+	entry *Entry
+}
+
+func newTypedDetail(data *model.DetailData, fetchedAt time.Time) (*typedDetail, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &typedDetail{data: data, entry: newEntry(body, fetchedAt)}, nil
+}
+
+// PredictionUpdate は新しく取得した予測データの情報
+type PredictionUpdate struct {
+	FetchedAt time.Time
+	Hash      string // ペイロードのSHA-256（16進数）
+	Data      []byte
+	Diff      *model.PredictionDiff // 前回の予測データとの差分（初回取得時や内容が変わらなかった場合はnil）
+}
+
+// PredictionListener は予測データの更新時に呼び出される関数
+type PredictionListener func(update PredictionUpdate)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// NewCacheManager は外部APIからデータを取得する新しいCacheManagerを初期化する
 func NewCacheManager(logger *slog.Logger, predictionURL string) *CacheManager {
+	return NewCacheManagerWithProviders(logger, provider.NewHTTPSet(predictionURL))
+}
+
+// NewCacheManagerWithProviders は指定したデータ取得元を使う新しいCacheManagerを初期化する
+func NewCacheManagerWithProviders(logger *slog.Logger, providers provider.Set) *CacheManager {
+	return NewCacheManagerWithOptions(logger, providers, DefaultOptions())
+}
+
+// Options はCacheManagerの動作の設定
+type Options struct {
+	RequestInterval time.Duration // 詳細データの取得で外部APIにリクエストするときの間隔（キャッシュ済みの潮汐データは待たない）
+	DiffHistory     int           // 保持する予測データの差分の件数
+}
+
+// DefaultOptions は既定の設定
+func DefaultOptions() Options {
+	return Options{RequestInterval: 250 * time.Millisecond, DiffHistory: maxPredictionDiffs}
+}
+
+// NewCacheManagerWithOptions は指定したデータ取得元と設定で新しいCacheManagerを初期化する
+func NewCacheManagerWithOptions(logger *slog.Logger, providers provider.Set, opts Options) *CacheManager {
+	if opts.DiffHistory <= 0 {
+		opts.DiffHistory = maxPredictionDiffs
+	}
 	cm := &CacheManager{
-		logger:        logger,
-		predictionURL: predictionURL,
+		logger:          logger,
+		providers:       providers,
+		spots:           spot.All(),
+		requestInterval: opts.RequestInterval,
+		diffHistory:     opts.DiffHistory,
 	}
-	cm.detailCache.data = make(map[string][]byte)
+	cm.detailCache.data = make(map[string]*Entry)
+	cm.detailCache.typed = make(map[string]*typedDetail)
+	cm.tideCache.data = make(map[string]map[string]interface{})
+	cm.tideCache.computed = make(map[string]map[string]interface{})
 	return cm
 }
 
 // FetchAndCachePredictionData は予測データを取得しキャッシュする
-func (c *CacheManager) FetchAndCachePredictionData() {
-	c.logger.Info("予測データの取得を試みています", "url", c.predictionURL)
-	resp, err := http.Get(c.predictionURL)
+func (c *CacheManager) FetchAndCachePredictionData() error {
+	c.logger.Info("予測データの取得を試みています", "source", c.providers.Prediction.Name())
+	body, err := c.providers.Prediction.FetchPrediction()
 	if err != nil {
 		c.logger.Error("予測データの取得に失敗しました", "error", err)
-		return
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Error("予測APIが正常なステータスを返しませんでした", "status_code", resp.StatusCode)
-		return
-	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.logger.Error("予測APIのレスポンスボディの読み取りに失敗しました", "error", err)
-		return
+		return fmt.Errorf("予測データの取得に失敗しました: %w", err)
 	}
 	if !json.Valid(body) {
 		c.logger.Error("取得した予測データは有効なJSONではありません")
-		return
+		return fmt.Errorf("取得した予測データは有効なJSONではありません")
 	}
+	entry := newEntry(body, time.Now())
 	c.predictionCache.Lock()
-	c.predictionCache.data = body
+	previous := c.predictionCache.entry
+	c.predictionCache.entry = entry
 	c.predictionCache.Unlock()
 	c.logger.Info("新しい予測データを正常に取得し、キャッシュしました")
+
+	var diff *model.PredictionDiff
+	if previous != nil && previous.Hash != entry.Hash {
+		d, err := DiffPredictions(previous, entry)
+		if err != nil {
+			c.logger.Warn("予測データの差分の計算に失敗しました", "error", err)
+		} else if len(d.Days) > 0 {
+			diff = d
+			c.recordPredictionDiff(*d)
+			c.logger.Info("予測データの変化を検出しました", "days", len(d.Days))
+		}
+	}
+
+	c.notifyPredictionListeners(PredictionUpdate{
+		FetchedAt: entry.FetchedAt,
+		Hash:      entry.Hash,
+		Data:      body,
+		Diff:      diff,
+	})
+	return nil
 }
 
-// FetchAndCacheDetailData は詳細データを取得しキャッシュする
-func (c *CacheManager) FetchAndCacheDetailData() {
-	c.logger.Info("詳細データの取得を開始します")
-	var wg sync.WaitGroup
-	jst, err := time.LoadLocation("Asia/Tokyo")
-	if err != nil {
-		c.logger.Error("JSTタイムゾーンの読み込みに失敗しました", "error", err)
-		return
+// AddPredictionListener は予測データ更新時に呼び出されるリスナーを登録する
+func (c *CacheManager) AddPredictionListener(listener PredictionListener) {
+	c.listenersMu.Lock()
+	defer c.listenersMu.Unlock()
+	c.predictionListeners = append(c.predictionListeners, listener)
+}
+
+func (c *CacheManager) notifyPredictionListeners(update PredictionUpdate) {
+	c.listenersMu.RLock()
+	listeners := make([]PredictionListener, len(c.predictionListeners))
+	copy(listeners, c.predictionListeners)
+	c.listenersMu.RUnlock()
+	for _, listener := range listeners {
+		listener(update)
 	}
-	for i := -1; i < 7; i++ {
-		wg.Add(1)
-		time.Sleep(250 * time.Millisecond) // APIへの負荷軽減
-		go func(dayOffset int) {
-			defer wg.Done()
-			targetDate := time.Now().In(jst).AddDate(0, 0, dayOffset)
-			dateStr := targetDate.Format("2006-01-02")
-			weatherData, err := c.fetchWeatherData(targetDate)
-			if err != nil {
-				c.logger.Error("気象データの取得に失敗しました", "date", dateStr, "error", err)
-				return
-			}
-			// 対象日の潮汐データを取得
-			tideData, err := c.fetchTideData(targetDate)
-			if err != nil {
-				c.logger.Error("潮汐データの取得に失敗しました", "date", dateStr, "error", err)
-				return
-			}
+}
 
-			// 翌日の潮汐データを取得（取得できない場合はnilで続行）
-			nextDate := targetDate.AddDate(0, 0, 1)
-			nextTideData, err := c.fetchTideData(nextDate)
-			if err != nil {
-				c.logger.Warn("翌日の潮汐データの取得に失敗しました（当日データのみでキャッシュします）", "date", nextDate.Format("2006-01-02"), "error", err)
-				nextTideData = nil
-			}
+// DetailResult は地点・日付ごとの詳細データ取得結果
+type DetailResult struct {
+	SpotID string
+	Date   string
+	Err    error
+	// TypedErr は型付きの形式（schema=2）に変換できなかった場合のエラー（従来の形式はキャッシュ済み）
+	TypedErr error
+}
 
-			// 2日分の潮汐データをまとめて格納（nextTideDataがnilの場合もあり）
-			combinedData := map[string]interface{}{
-				"weather":  weatherData,
-				"tide":     tideData,
-				"nextTide": nextTideData, // 翌日の潮汐データを追加（nilの場合あり）
-			}
-			jsonData, err := json.Marshal(combinedData)
-			if err != nil {
-				c.logger.Error("詳細データのJSONシリアライズに失敗しました", "date", dateStr, "error", err)
-				return
-			}
-			c.detailCache.Lock()
-			c.detailCache.data[dateStr] = jsonData
-			c.detailCache.Unlock()
-			c.logger.Info("詳細データを正常に取得しキャッシュしました", "date", dateStr)
-		}(i)
+// FetchAndCacheDetailData は全地点の詳細データを取得しキャッシュする
+// 地点・日付ごとの成否を返す
+func (c *CacheManager) FetchAndCacheDetailData() []DetailResult {
+	c.logger.Info("詳細データの取得を開始します", "spots", len(c.spots))
+	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+	var results []DetailResult
+	oldest := time.Now().In(jst).AddDate(0, 0, -1).Format("2006-01-02")
+	c.pruneTideCache(oldest)
+	// 潮汐は湾内の全地点で共通なので日付ごとに1回だけ取得する
+	tides := &tideMemo{entries: make(map[string]*tideMemoEntry)}
+	for i := -1; i < 7; i++ {
+		for _, s := range c.spots {
+			wg.Add(1)
+			go func(dayOffset int, s spot.Spot) {
+				defer wg.Done()
+				targetDate := time.Now().In(jst).AddDate(0, 0, dayOffset)
+				typedErr, err := c.fetchAndCacheSpotDetail(s, targetDate, tides)
+				resultsMu.Lock()
+				results = append(results, DetailResult{SpotID: s.ID, Date: targetDate.Format("2006-01-02"), Err: err, TypedErr: typedErr})
+				resultsMu.Unlock()
+			}(i, s)
+		}
 	}
 	wg.Wait()
+	// 昨日より前の詳細データは表示対象外なので、キャッシュ（とスナップショット）に溜まらないよう削除する
+	c.pruneDetailCache(oldest)
 	c.logger.Info("詳細データの取得が完了しました")
+	return results
 }
 
-func (c *CacheManager) fetchWeatherData(targetDate time.Time) (map[string]interface{}, error) {
-	startDate := targetDate.Format("2006-01-02")
-	endDate := targetDate.AddDate(0, 0, 1).Format("2006-01-02")
-	weatherApiUrl := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=36.76&longitude=137.24&hourly=temperature_2m,precipitation,precipitation_probability,weather_code,wind_speed_10m,wind_direction_10m&timezone=Asia%%2FTokyo&wind_speed_unit=ms&start_date=%s&end_date=%s", startDate, endDate)
-	resp, err := http.Get(weatherApiUrl)
+// fetchAndCacheSpotDetail は1地点・1日分の詳細データを取得しキャッシュする
+// 型付きの形式に変換できなかった場合は、従来の形式だけをキャッシュしてtypedErrを返す
+func (c *CacheManager) fetchAndCacheSpotDetail(s spot.Spot, targetDate time.Time, tides *tideMemo) (typedErr error, err error) {
+	dateStr := targetDate.Format("2006-01-02")
+	c.waitForRequest()
+	weatherData, err := c.providers.Weather.FetchWeather(s, targetDate)
 	if err != nil {
-		return nil, err
+		c.logger.Error("気象データの取得に失敗しました", "spot", s.ID, "date", dateStr, "error", err)
+		return nil, fmt.Errorf("気象データの取得に失敗しました: %w", err)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("気象APIが正常なステータスを返しませんでした: %d", resp.StatusCode)
+	// 対象日の潮汐データを取得
+	tideData, err := tides.get(s, targetDate, c.fetchTide)
+	if err != nil {
+		c.logger.Error("潮汐データの取得に失敗しました", "spot", s.ID, "date", dateStr, "error", err)
+		return nil, fmt.Errorf("潮汐データの取得に失敗しました: %w", err)
 	}
-	body, err := io.ReadAll(resp.Body)
+
+	// 翌日の潮汐データを取得（取得できない場合はnilで続行）
+	nextDate := targetDate.AddDate(0, 0, 1)
+	nextTideData, err := tides.get(s, nextDate, c.fetchTide)
 	if err != nil {
-		return nil, err
+		c.logger.Warn("翌日の潮汐データの取得に失敗しました（当日データのみでキャッシュします）", "spot", s.ID, "date", nextDate.Format("2006-01-02"), "error", err)
+		nextTideData = nil
 	}
-	var data map[string]interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
-		return nil, err
+
+	// 2日分の潮汐データをまとめて格納（nextTideDataがnilの場合もあり）
+	combinedData := map[string]interface{}{
+		"spot":     s,
+		"weather":  weatherData,
+		"tide":     tideData,
+		"nextTide": nextTideData, // 翌日の潮汐データを追加（nilの場合あり）
+	}
+	jsonData, err := json.Marshal(combinedData)
+	if err != nil {
+		c.logger.Error("詳細データのJSONシリアライズに失敗しました", "spot", s.ID, "date", dateStr, "error", err)
+		return nil, fmt.Errorf("詳細データのJSONシリアライズに失敗しました: %w", err)
 	}
-	return data, nil
+	// 従来の形式（schema=1）は常にキャッシュする
+	// 型付きの形式（schema=2）に変換できない場合は、古い型付きデータが残らないよう削除する
+	fetchedAt := time.Now()
+	key := detailKey(s.ID, dateStr)
+	var typed *typedDetail
+	detail, typedErr := normalizeDetail(jsonData, dateStr, fetchedAt)
+	if typedErr == nil {
+		typed, typedErr = newTypedDetail(detail, fetchedAt)
+	}
+	if typedErr != nil {
+		c.logger.Warn("詳細データを型付きの形式に変換できませんでした（従来の形式のみキャッシュします）", "spot", s.ID, "date", dateStr, "error", typedErr)
+	}
+	entry := newEntry(jsonData, fetchedAt)
+	c.detailCache.Lock()
+	c.detailCache.data[key] = entry
+	if typed != nil {
+		c.detailCache.typed[key] = typed
+	} else {
+		delete(c.detailCache.typed, key)
+	}
+	c.detailCache.Unlock()
+	if typedErr != nil {
+		return fmt.Errorf("型付きの形式（schema=2）に変換できませんでした: %w", typedErr), nil
+	}
+	c.logger.Info("詳細データを正常に取得しキャッシュしました", "spot", s.ID, "date", dateStr)
+	return nil, nil
 }
 
-func (c *CacheManager) fetchTideData(targetDate time.Time) (map[string]interface{}, error) {
-	year := targetDate.Year()
-	month := int(targetDate.Month())
-	day := targetDate.Day()
-	tideApiUrl := fmt.Sprintf("https://tide736.net/api/get_tide.php?pc=16&hc=3&yr=%d&mn=%d&dy=%d&rg=day", year, month, day)
-	resp, err := http.Get(tideApiUrl)
-	if err != nil {
-		return nil, err
+// fetchTide は潮汐データを取得する
+// 以前の更新で取得済みの日付はキャッシュを使い、取得に失敗した場合は代替の取得元（調和定数による推算）を使う
+func (c *CacheManager) fetchTide(s spot.Spot, date time.Time) (map[string]interface{}, error) {
+	key := tideKey(date)
+	c.tideCache.RLock()
+	cached, ok := c.tideCache.data[key]
+	c.tideCache.RUnlock()
+	if ok {
+		return cached, nil
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("潮汐APIが正常なステータスを返しませんでした: %d", resp.StatusCode)
+
+	c.waitForRequest()
+	data, err := c.providers.Tide.FetchTide(s, date)
+	if err == nil {
+		c.tideCache.Lock()
+		c.tideCache.data[key] = data
+		delete(c.tideCache.computed, key)
+		c.tideCache.Unlock()
+		return data, nil
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+	if c.providers.TideFallback == nil {
 		return nil, err
 	}
-	var data map[string]interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
-		return nil, err
+
+	// 推算値は日付が決まれば変わらないので、前回の更新で推算した値があればそれを使う
+	c.tideCache.RLock()
+	computed, ok := c.tideCache.computed[key]
+	c.tideCache.RUnlock()
+	if ok {
+		c.logger.Debug("潮汐データの取得に失敗したため推算値を使います", "date", key, "error", err)
+		return computed, nil
+	}
+	c.logger.Warn("潮汐データの取得に失敗したため推算値を使います", "date", key, "source", c.providers.TideFallback.Name(), "error", err)
+	computed, fallbackErr := c.providers.TideFallback.FetchTide(s, date)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%w（推算にも失敗しました: %v）", err, fallbackErr)
+	}
+	c.tideCache.Lock()
+	c.tideCache.computed[key] = computed
+	c.tideCache.Unlock()
+	return computed, nil
+}
+
+// detailKey は詳細キャッシュのキーを地点IDと日付から作る
+func detailKey(spotID, dateStr string) string {
+	return spotID + ":" + dateStr
+}
+
+// pruneTideCache はoldestより前の日付の潮汐データをキャッシュから捨てる
+func (c *CacheManager) pruneTideCache(oldest string) {
+	c.tideCache.Lock()
+	defer c.tideCache.Unlock()
+	for _, m := range []map[string]map[string]interface{}{c.tideCache.data, c.tideCache.computed} {
+		for key := range m {
+			if date := key[len(key)-len("2006-01-02"):]; date < oldest {
+				delete(m, key)
+			}
+		}
+	}
+}
+
+// pruneDetailCache はoldestより前の日付の詳細データを削除する
+func (c *CacheManager) pruneDetailCache(oldest string) {
+	c.detailCache.Lock()
+	defer c.detailCache.Unlock()
+	for key := range c.detailCache.data {
+		if date := key[len(key)-len("2006-01-02"):]; date < oldest {
+			delete(c.detailCache.data, key)
+		}
+	}
+	for key := range c.detailCache.typed {
+		if date := key[len(key)-len("2006-01-02"):]; date < oldest {
+			delete(c.detailCache.typed, key)
+		}
 	}
-	return data, nil
+}
+
+// waitForRequest は前回の外部APIへのリクエストからrequestInterval経つまで待つ（APIへの負荷軽減）
+func (c *CacheManager) waitForRequest() {
+	c.throttle.Lock()
+	defer c.throttle.Unlock()
+	if wait := c.requestInterval - time.Since(c.throttle.last); wait > 0 {
+		time.Sleep(wait)
+	}
+	c.throttle.last = time.Now()
+}
+
+// tideKey は潮汐データのキーを日付から作る（潮汐は富山湾全体で1つ。spot.TidePrefCodeを参照）
+func tideKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// tideMemo は1回の更新処理の中で日付ごとの潮汐データ取得を1回にまとめる
+type tideMemo struct {
+	mu      sync.Mutex
+	entries map[string]*tideMemoEntry
+}
+
+type tideMemoEntry struct {
+	once sync.Once
+	data map[string]interface{}
+	err  error
+}
+
+func (m *tideMemo) get(s spot.Spot, date time.Time, fetch func(spot.Spot, time.Time) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	key := tideKey(date)
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &tideMemoEntry{}
+		m.entries[key] = entry
+	}
+	m.mu.Unlock()
+	entry.once.Do(func() {
+		entry.data, entry.err = fetch(s, date)
+	})
+	return entry.data, entry.err
 }
 
 // GetPredictionData はキャッシュされた予測データを返す
 func (c *CacheManager) GetPredictionData() []byte {
+	entry, ok := c.GetPredictionEntry()
+	if !ok {
+		return nil
+	}
+	return entry.Data
+}
+
+// GetPredictionEntry はキャッシュされた予測データを取得時刻・ハッシュとともに返す
+func (c *CacheManager) GetPredictionEntry() (*Entry, bool) {
 	c.predictionCache.RLock()
 	defer c.predictionCache.RUnlock()
-	return c.predictionCache.data
+	return c.predictionCache.entry, c.predictionCache.entry != nil
 }
 
-// GetDetailData はキャッシュされた指定日の詳細データを返す
-func (c *CacheManager) GetDetailData(dateStr string) ([]byte, bool) {
+// GetDetailData はキャッシュされた指定地点・指定日の詳細データを返す
+func (c *CacheManager) GetDetailData(spotID, dateStr string) ([]byte, bool) {
+	entry, ok := c.GetDetailEntry(spotID, dateStr)
+	if !ok {
+		return nil, false
+	}
+	return entry.Data, true
+}
+
+// GetDetailEntry はキャッシュされた指定地点・指定日の詳細データを取得時刻・ハッシュとともに返す
+func (c *CacheManager) GetDetailEntry(spotID, dateStr string) (*Entry, bool) {
+	c.detailCache.RLock()
+	defer c.detailCache.RUnlock()
+	entry, ok := c.detailCache.data[detailKey(spotID, dateStr)]
+	return entry, ok
+}
+
+// GetDetailTypedEntry はキャッシュされた指定地点・指定日の型付き詳細データ（schema=2）のJSONを返す
+func (c *CacheManager) GetDetailTypedEntry(spotID, dateStr string) (*Entry, bool) {
 	c.detailCache.RLock()
 	defer c.detailCache.RUnlock()
-	data, ok := c.detailCache.data[dateStr]
-	return data, ok
-}
\ No newline at end of file
+	typed, ok := c.detailCache.typed[detailKey(spotID, dateStr)]
+	if !ok {
+		return nil, false
+	}
+	return typed.entry, true
+}
+
+// GetDetail はキャッシュされた指定地点・指定日の型付き詳細データを返す
+// 返した値は他の呼び出し元と共有されるため変更しないこと
+func (c *CacheManager) GetDetail(spotID, dateStr string) (*model.DetailData, bool) {
+	c.detailCache.RLock()
+	defer c.detailCache.RUnlock()
+	typed, ok := c.detailCache.typed[detailKey(spotID, dateStr)]
+	if !ok {
+		return nil, false
+	}
+	return typed.data, true
+}
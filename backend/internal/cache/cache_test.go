@@ -3,9 +3,18 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/harmonic"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
 )
 
 // TestCombinedDataWithNilNextTide はnextTideDataがnilの場合でも
@@ -81,11 +90,11 @@ func TestCacheStoreAndRetrieveWithNilNextTide(t *testing.T) {
 		testDateStr := "2026-02-23"
 
 		cm.detailCache.Lock()
-		cm.detailCache.data[testDateStr] = jsonData
+		cm.detailCache.data[detailKey(spot.DefaultID, testDateStr)] = newEntry(jsonData, time.Now())
 		cm.detailCache.Unlock()
 
 		// 取得して確認
-		data, ok := cm.GetDetailData(testDateStr)
+		data, ok := cm.GetDetailData(spot.DefaultID, testDateStr)
 		if !ok {
 			t.Fatal("キャッシュデータの取得に失敗しました")
 		}
@@ -102,3 +111,472 @@ func TestCacheStoreAndRetrieveWithNilNextTide(t *testing.T) {
 		t.Logf("nextTideがnilのデータをキャッシュに正しく保存・取得できました")
 	})
 }
+
+// failingTideProvider は常に失敗する潮汐プロバイダ（tide736の障害を想定）
+type failingTideProvider struct{}
+
+func (failingTideProvider) Name() string { return "failing" }
+
+func (failingTideProvider) FetchTide(spot.Spot, time.Time) (map[string]interface{}, error) {
+	return nil, errors.New("tide736 is down")
+}
+
+func newFixtureCacheManager(t *testing.T) *CacheManager {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cm := NewCacheManagerWithProviders(logger, provider.NewFixtureSet("../../testdata/fixtures"))
+	cm.spots = []spot.Spot{spot.Default()}
+	cm.requestInterval = 0
+	return cm
+}
+
+// countingTideProvider は潮汐データの取得回数を数える
+type countingTideProvider struct {
+	provider.TideProvider
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *countingTideProvider) FetchTide(s spot.Spot, date time.Time) (map[string]interface{}, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	return p.TideProvider.FetchTide(s, date)
+}
+
+// TestFetchAndCacheDetailDataCachesTidePerHarbor は湾内の全地点で共通の潮汐データを日付ごとに1回だけ取得し、
+// 次の更新では取得済みのものを使うことをテストする
+func TestFetchAndCacheDetailDataCachesTidePerHarbor(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	cm.spots = spot.All()
+	tides := &countingTideProvider{TideProvider: cm.providers.Tide}
+	cm.providers.Tide = tides
+
+	cm.FetchAndCacheDetailData()
+	// 昨日〜6日後と、6日後の翌日の潮汐
+	if tides.calls != 9 {
+		t.Errorf("1回目の更新での潮汐データの取得回数 = %d, want 9", tides.calls)
+	}
+	cm.FetchAndCacheDetailData()
+	if tides.calls != 9 {
+		t.Errorf("2回目の更新で潮汐データを取得し直しています: %d回", tides.calls)
+	}
+
+	cm.pruneTideCache(time.Now().In(jst).AddDate(0, 0, 7).Format("2006-01-02"))
+	if n := len(cm.tideCache.data); n != 1 {
+		t.Errorf("古い日付の潮汐データが残っています: %d件", n)
+	}
+}
+
+// TestFetchAndCacheDetailDataWithFixtures はフィクスチャから詳細データを取得し
+// 対象期間（昨日〜6日後）の全日付がキャッシュされることをテストする
+func TestFetchAndCacheDetailDataWithFixtures(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	cm.FetchAndCacheDetailData()
+
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	for i := -1; i < 7; i++ {
+		dateStr := time.Now().In(jst).AddDate(0, 0, i).Format("2006-01-02")
+		data, ok := cm.GetDetailData(spot.DefaultID, dateStr)
+		if !ok {
+			t.Fatalf("%sの詳細データがキャッシュされていません", dateStr)
+		}
+
+		var result struct {
+			Weather struct {
+				Hourly struct {
+					Time []string `json:"time"`
+				} `json:"hourly"`
+			} `json:"weather"`
+			Tide struct {
+				Tide struct {
+					Chart map[string]json.RawMessage `json:"chart"`
+				} `json:"tide"`
+			} `json:"tide"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatalf("キャッシュデータのアンマーシャルに失敗: %v", err)
+		}
+		// 記録データの日付が対象日にずらされていること
+		if len(result.Weather.Hourly.Time) == 0 || result.Weather.Hourly.Time[0] != dateStr+"T00:00" {
+			t.Errorf("%s: 気象データの先頭時刻が不正です: %v", dateStr, result.Weather.Hourly.Time)
+		}
+		if _, ok := result.Tide.Tide.Chart[dateStr]; !ok {
+			t.Errorf("%s: 潮汐データに対象日のchartがありません", dateStr)
+		}
+	}
+}
+
+// TestFetchAndCacheDetailDataPrunesOldDates は更新のたびに昨日より前の日付の詳細データを削除することをテストする
+func TestFetchAndCacheDetailDataPrunesOldDates(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	cm.FetchAndCacheDetailData()
+
+	// 3日前に取得した詳細データが残っている状態にする
+	old := time.Now().In(jst).AddDate(0, 0, -3).Format("2006-01-02")
+	yesterday := time.Now().In(jst).AddDate(0, 0, -1).Format("2006-01-02")
+	cm.detailCache.Lock()
+	cm.detailCache.data[detailKey(spot.DefaultID, old)] = cm.detailCache.data[detailKey(spot.DefaultID, yesterday)]
+	cm.detailCache.typed[detailKey(spot.DefaultID, old)] = cm.detailCache.typed[detailKey(spot.DefaultID, yesterday)]
+	cm.detailCache.Unlock()
+
+	cm.FetchAndCacheDetailData()
+	if _, ok := cm.GetDetailEntry(spot.DefaultID, old); ok {
+		t.Errorf("%sの詳細データが削除されていません", old)
+	}
+	if _, ok := cm.GetDetail(spot.DefaultID, old); ok {
+		t.Errorf("%sの型付きの詳細データが削除されていません", old)
+	}
+	if _, ok := cm.GetDetailEntry(spot.DefaultID, yesterday); !ok {
+		t.Errorf("昨日（%s）の詳細データが削除されました", yesterday)
+	}
+}
+
+// TestFetchAndCacheDetailDataTideFailure は潮汐データが取得できず代替の取得元もない場合に
+// その日付がキャッシュされないことをテストする
+func TestFetchAndCacheDetailDataTideFailure(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	cm.providers.Tide = failingTideProvider{}
+	cm.providers.TideFallback = nil
+	cm.FetchAndCacheDetailData()
+
+	dateStr := time.Now().In(time.FixedZone("Asia/Tokyo", 9*60*60)).Format("2006-01-02")
+	if _, ok := cm.GetDetailData(spot.DefaultID, dateStr); ok {
+		t.Error("潮汐データの取得に失敗した日付がキャッシュされています")
+	}
+}
+
+// TestFetchAndCacheDetailDataTideFallback は潮汐データが取得できない場合に
+// 調和定数による推算値でキャッシュされ、computedとして示されることをテストする
+func TestFetchAndCacheDetailDataTideFallback(t *testing.T) {
+	// 推算の値そのものは見ないので、主要8分潮のうちS2だけに振幅のある調和定数を使う
+	path := filepath.Join(t.TempDir(), "stations.json")
+	body := `{"source": "テスト用", "stations": [{"name": "富山", "pref_code": 16, "harbor_code": 3, "z0": 20,
+		"constituents": [{"name": "M2", "amplitude": 0, "phase": 0}, {"name": "S2", "amplitude": 10, "phase": 0},
+		{"name": "N2", "amplitude": 0, "phase": 0}, {"name": "K2", "amplitude": 0, "phase": 0},
+		{"name": "K1", "amplitude": 0, "phase": 0}, {"name": "O1", "amplitude": 0, "phase": 0},
+		{"name": "P1", "amplitude": 0, "phase": 0}, {"name": "Q1", "amplitude": 0, "phase": 0}]}]}`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := harmonic.LoadTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm := newFixtureCacheManager(t)
+	cm.providers.Tide = failingTideProvider{}
+	fallback := &countingTideProvider{TideProvider: provider.NewHarmonicTide(table)}
+	cm.providers.TideFallback = fallback
+	cm.FetchAndCacheDetailData()
+	// 推算した値は次の更新でも使い、同じ日付を推算し直さない
+	cm.FetchAndCacheDetailData()
+	if fallback.calls != 9 {
+		t.Errorf("2回の更新での推算の回数 = %d, want 9", fallback.calls)
+	}
+
+	dateStr := time.Now().In(jst).Format("2006-01-02")
+	detail, ok := cm.GetDetail(spot.DefaultID, dateStr)
+	if !ok {
+		t.Fatal("推算値で詳細データがキャッシュされていません")
+	}
+	if detail.Tide.Source != "computed" || detail.NextTide == nil || detail.NextTide.Source != "computed" {
+		t.Errorf("潮汐データの取得元がcomputedになっていません: %q", detail.Tide.Source)
+	}
+	if len(detail.Tide.Curve) != 73 || detail.Tide.TideName == "" || detail.Tide.Moon.Age == nil {
+		t.Errorf("推算した潮汐データが不完全です: curve=%d, name=%q", len(detail.Tide.Curve), detail.Tide.TideName)
+	}
+
+	// 従来の形式でも推算値であることが分かる
+	data, _ := cm.GetDetailData(spot.DefaultID, dateStr)
+	var raw struct {
+		Tide struct {
+			Source string `json:"source"`
+		} `json:"tide"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil || raw.Tide.Source != "computed" {
+		t.Errorf("従来の形式にsourceが含まれていません: %v", err)
+	}
+
+	// tide736.netが復旧したら推算値を置き換える
+	cm.providers.Tide = provider.NewFixtureSet("../../testdata/fixtures").Tide
+	cm.FetchAndCacheDetailData()
+	if detail, _ := cm.GetDetail(spot.DefaultID, dateStr); detail.Tide.Source == "computed" {
+		t.Error("tide736.netの復旧後も推算値が使われています")
+	}
+	if n := len(cm.tideCache.computed); n != 0 {
+		t.Errorf("復旧後に残っている推算値 = %d, want 0", n)
+	}
+}
+
+// TestFetchAndCachePredictionDataWithFixtures は予測データがキャッシュされ
+// リスナーに通知されることをテストする
+func TestFetchAndCachePredictionDataWithFixtures(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	var notified []PredictionUpdate
+	cm.AddPredictionListener(func(update PredictionUpdate) {
+		notified = append(notified, update)
+	})
+	cm.FetchAndCachePredictionData()
+
+	data := cm.GetPredictionData()
+	if data == nil {
+		t.Fatal("予測データがキャッシュされていません")
+	}
+	if len(notified) != 1 {
+		t.Fatalf("リスナーの呼び出し回数 = %d, want 1", len(notified))
+	}
+	if len(notified[0].Hash) != 64 {
+		t.Errorf("ハッシュの形式が不正です: %q", notified[0].Hash)
+	}
+}
+
+// TestSnapshotRoundTrip はスナップショットから復元したデータが
+// 古いもの（Stale）として扱われ、再取得で置き換わることをテストする
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := t.TempDir() + "/snapshot.json"
+
+	cm := newFixtureCacheManager(t)
+	cm.SetSnapshotStore(NewFileSnapshotStore(path))
+	if err := cm.FetchAndCachePredictionData(); err != nil {
+		t.Fatalf("予測データの取得に失敗: %v", err)
+	}
+	cm.FetchAndCacheDetailData()
+	if err := cm.SaveSnapshot(); err != nil {
+		t.Fatalf("スナップショットの保存に失敗: %v", err)
+	}
+
+	restored := newFixtureCacheManager(t)
+	restored.SetSnapshotStore(NewFileSnapshotStore(path))
+	if err := restored.LoadSnapshot(); err != nil {
+		t.Fatalf("スナップショットの読み込みに失敗: %v", err)
+	}
+
+	entry, ok := restored.GetPredictionEntry()
+	if !ok || !entry.Stale {
+		t.Fatal("予測データが古いデータとして復元されていません")
+	}
+	original, _ := cm.GetPredictionEntry()
+	if entry.Hash != original.Hash || !entry.FetchedAt.Equal(original.FetchedAt) {
+		t.Error("復元した予測データが保存時と一致しません")
+	}
+	dateStr := time.Now().In(jst).Format("2006-01-02")
+	if detail, ok := restored.GetDetailEntry(spot.DefaultID, dateStr); !ok || !detail.Stale {
+		t.Error("詳細データが古いデータとして復元されていません")
+	}
+
+	// 再取得すると新しいデータに置き換わる
+	restored.FetchAndCachePredictionData()
+	if entry, _ := restored.GetPredictionEntry(); entry.Stale {
+		t.Error("再取得後も予測データが古いデータのままです")
+	}
+}
+
+// jsonbSnapshotStore はJSONBのように空白やキーの順序を変えて保存するスナップショットの保存先
+type jsonbSnapshotStore struct {
+	body []byte
+}
+
+func (j *jsonbSnapshotStore) Save(snapshot *Snapshot) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return err
+	}
+	j.body, err = json.MarshalIndent(v, "", " ")
+	return err
+}
+
+func (j *jsonbSnapshotStore) Load() (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.Unmarshal(j.body, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// TestSnapshotKeepsETag は保存先でJSONの書式が変わっても、保存時と同じバイト列とETagを復元することをテストする
+func TestSnapshotKeepsETag(t *testing.T) {
+	store := &jsonbSnapshotStore{}
+	cm := newFixtureCacheManager(t)
+	cm.SetSnapshotStore(store)
+	if err := cm.FetchAndCachePredictionData(); err != nil {
+		t.Fatalf("予測データの取得に失敗: %v", err)
+	}
+	cm.FetchAndCacheDetailData()
+	if err := cm.SaveSnapshot(); err != nil {
+		t.Fatalf("スナップショットの保存に失敗: %v", err)
+	}
+
+	restored := newFixtureCacheManager(t)
+	restored.SetSnapshotStore(store)
+	if err := restored.LoadSnapshot(); err != nil {
+		t.Fatalf("スナップショットの読み込みに失敗: %v", err)
+	}
+
+	original, _ := cm.GetPredictionEntry()
+	entry, ok := restored.GetPredictionEntry()
+	if !ok {
+		t.Fatal("予測データが復元されていません")
+	}
+	if string(entry.Data) != string(original.Data) || entry.ETag() != original.ETag() {
+		t.Errorf("予測データのETag = %s, want %s（データが一致: %v）", entry.ETag(), original.ETag(), string(entry.Data) == string(original.Data))
+	}
+	dateStr := time.Now().In(jst).Format("2006-01-02")
+	originalDetail, _ := cm.GetDetailEntry(spot.DefaultID, dateStr)
+	detail, ok := restored.GetDetailEntry(spot.DefaultID, dateStr)
+	if !ok {
+		t.Fatal("詳細データが復元されていません")
+	}
+	if string(detail.Data) != string(originalDetail.Data) || detail.ETag() != originalDetail.ETag() {
+		t.Errorf("詳細データのETag = %s, want %s", detail.ETag(), originalDetail.ETag())
+	}
+}
+
+// TestSnapshotLegacyDataRecomputesHash は以前の形式（dataにJSONのまま保存）のスナップショットでは
+// 読み込んだバイト列からハッシュを計算し直し、配信するデータとETagが食い違わないことをテストする
+func TestSnapshotLegacyDataRecomputesHash(t *testing.T) {
+	store := &jsonbSnapshotStore{body: []byte(`{"saved_at": "2026-04-10T12:00:00Z",
+		"prediction": {"data": [ {"date": "2026-04-10"} ], "fetched_at": "2026-04-10T12:00:00Z", "hash": "stale-hash"},
+		"details": {}}`)}
+	cm := newFixtureCacheManager(t)
+	cm.SetSnapshotStore(store)
+	if err := cm.LoadSnapshot(); err != nil {
+		t.Fatalf("スナップショットの読み込みに失敗: %v", err)
+	}
+	entry, ok := cm.GetPredictionEntry()
+	if !ok {
+		t.Fatal("予測データが復元されていません")
+	}
+	if want := newEntry(entry.Data, entry.FetchedAt).Hash; entry.Hash != want {
+		t.Errorf("Hash = %s, want %s（復元したデータのハッシュ）", entry.Hash, want)
+	}
+}
+
+// TestFetchAndCacheDetailDataTyped はフィクスチャから取得した詳細データが
+// 型付きの形式（schema=2）に変換されてキャッシュされることをテストする
+func TestFetchAndCacheDetailDataTyped(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	cm.FetchAndCacheDetailData()
+
+	dateStr := time.Now().In(jst).Format("2006-01-02")
+	detail, ok := cm.GetDetail(spot.DefaultID, dateStr)
+	if !ok {
+		t.Fatal("型付きの詳細データがキャッシュされていません")
+	}
+	if detail.SchemaVersion != model.DetailSchemaVersion || detail.Spot.ID != spot.DefaultID {
+		t.Errorf("スキーマバージョンまたは地点が不正です: %d, %s", detail.SchemaVersion, detail.Spot.ID)
+	}
+	if len(detail.Weather) != 48 || detail.Weather[0].Time.Format("2006-01-02T15:04") != dateStr+"T00:00" {
+		t.Errorf("気象データが不正です: %d件", len(detail.Weather))
+	}
+	if len(detail.Tide.Curve) == 0 || len(detail.Tide.Events) == 0 {
+		t.Fatal("潮汐データが空です")
+	}
+	// 潮位曲線の最後の"24:00"は翌日0時として扱う
+	last := detail.Tide.Curve[len(detail.Tide.Curve)-1].Time
+	if want := detail.Tide.Curve[0].Time.AddDate(0, 0, 1); !last.Equal(want) {
+		t.Errorf("潮位曲線の最後の時刻 = %v, want %v", last, want)
+	}
+	if detail.NextTide == nil || detail.NextTide.Date <= dateStr {
+		t.Error("翌日の潮汐データが変換されていません")
+	}
+
+	if _, ok := cm.GetDetailTypedEntry(spot.DefaultID, dateStr); !ok {
+		t.Error("型付きの詳細データのJSONがキャッシュされていません")
+	}
+}
+
+// nullWeatherProvider は気温にnullを含む気象データを返す（Open-Meteoの予報範囲外の時間を想定）
+type nullWeatherProvider struct {
+	provider.WeatherProvider
+}
+
+func (p nullWeatherProvider) FetchWeather(s spot.Spot, date time.Time) (map[string]interface{}, error) {
+	data, err := p.WeatherProvider.FetchWeather(s, date)
+	if err != nil {
+		return nil, err
+	}
+	hourly := data["hourly"].(map[string]interface{})
+	temps := hourly["temperature_2m"].([]interface{})
+	temps[len(temps)-1] = nil
+	return data, nil
+}
+
+// TestFetchAndCacheDetailDataKeepsLegacyOnNormalizeFailure は型付きの形式に変換できない場合も
+// 従来の形式はキャッシュされ、型付きの形式だけが除かれることをテストする
+func TestFetchAndCacheDetailDataKeepsLegacyOnNormalizeFailure(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	cm.FetchAndCacheDetailData()
+	dateStr := time.Now().In(jst).Format("2006-01-02")
+	if _, ok := cm.GetDetail(spot.DefaultID, dateStr); !ok {
+		t.Fatal("型付きの詳細データがキャッシュされていません")
+	}
+
+	cm.providers.Weather = nullWeatherProvider{cm.providers.Weather}
+	cm.FetchAndCacheDetailData()
+	if _, ok := cm.GetDetailData(spot.DefaultID, dateStr); !ok {
+		t.Error("従来の形式の詳細データがキャッシュされていません")
+	}
+	if _, ok := cm.GetDetail(spot.DefaultID, dateStr); ok {
+		t.Error("変換できなかった日付に古い型付きの詳細データが残っています")
+	}
+}
+
+// malformedWeatherProvider は気温の要素数が時刻と合わない気象データを返す
+type malformedWeatherProvider struct {
+	provider.WeatherProvider
+}
+
+func (p malformedWeatherProvider) FetchWeather(s spot.Spot, date time.Time) (map[string]interface{}, error) {
+	data, err := p.WeatherProvider.FetchWeather(s, date)
+	if err != nil {
+		return nil, err
+	}
+	hourly := data["hourly"].(map[string]interface{})
+	temps := hourly["temperature_2m"].([]interface{})
+	hourly["temperature_2m"] = temps[:len(temps)-1]
+	return data, nil
+}
+
+// TestFetchAndCacheDetailDataReportsTypedFailure は不正な外部APIのレスポンスを型付きの形式に
+// 変換できなかったことを取得結果として返すことをテストする
+func TestFetchAndCacheDetailDataReportsTypedFailure(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	cm.providers.Weather = malformedWeatherProvider{cm.providers.Weather}
+	results := cm.FetchAndCacheDetailData()
+	if len(results) == 0 {
+		t.Fatal("取得結果がありません")
+	}
+	for _, r := range results {
+		if r.Err != nil || r.TypedErr == nil {
+			t.Errorf("%s %s: Err = %v, TypedErr = %v", r.SpotID, r.Date, r.Err, r.TypedErr)
+		}
+	}
+	dateStr := time.Now().In(jst).Format("2006-01-02")
+	if _, ok := cm.GetDetailData(spot.DefaultID, dateStr); !ok {
+		t.Error("従来の形式の詳細データがキャッシュされていません")
+	}
+	if _, ok := cm.GetDetailTypedEntry(spot.DefaultID, dateStr); ok {
+		t.Error("変換できなかった型付きの詳細データがキャッシュされています")
+	}
+}
+
+// TestNormalizeDetailRejectsMalformed は不正な外部APIのレスポンスが変換エラーになることをテストする
+func TestNormalizeDetailRejectsMalformed(t *testing.T) {
+	tests := map[string]string{
+		"気象データの要素数の不一致": `{"spot":{"id":"iwasehama"},"weather":{"hourly":{"time":["2026-04-10T00:00"],"temperature_2m":[]}},"tide":{"tide":{"chart":{"2026-04-10":{"tide":[{"time":"00:00","cm":1}]}}}}}`,
+		"対象日の潮汐データがない":  `{"spot":{"id":"iwasehama"},"weather":{"hourly":{"time":["2026-04-10T00:00"],"temperature_2m":[1],"precipitation":[0],"precipitation_probability":[0],"weather_code":[0],"wind_speed_10m":[1],"wind_direction_10m":[0]}},"tide":{"tide":{"chart":{}}}}`,
+		"潮位の値が不正":       `{"spot":{"id":"iwasehama"},"weather":{"hourly":{"time":["2026-04-10T00:00"],"temperature_2m":[1],"precipitation":[0],"precipitation_probability":[0],"weather_code":[0],"wind_speed_10m":[1],"wind_direction_10m":[0]}},"tide":{"tide":{"chart":{"2026-04-10":{"tide":[{"time":"00:00","cm":"abc"}]}}}}}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := normalizeDetail([]byte(data), "2026-04-10", time.Now()); err == nil {
+				t.Error("エラーを期待しましたが、変換に成功しました")
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// backend/internal/cache/diff.go
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// 保持する予測データの差分の件数の既定値
+const maxPredictionDiffs = 20
+
+// MaterialDelta は大きな変化とみなす予測値の変化幅（レベルが変わらなくても変化とみなす）
+const MaterialDelta = 0.15
+
+// 差分の状態
+const (
+	DiffChanged = "changed"
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+)
+
+// DiffPredictions は更新前後の予測データを日付ごとに比較する
+// 予測が変わった日がない場合もDaysが空の差分を返す
+func DiffPredictions(previous, current *Entry) (*model.PredictionDiff, error) {
+	prevDays, err := parsePredictionDays(previous.Data)
+	if err != nil {
+		return nil, fmt.Errorf("更新前の予測データの解析失敗: %w", err)
+	}
+	currDays, err := parsePredictionDays(current.Data)
+	if err != nil {
+		return nil, fmt.Errorf("更新後の予測データの解析失敗: %w", err)
+	}
+
+	diff := &model.PredictionDiff{
+		FromFetchedAt: previous.FetchedAt,
+		ToFetchedAt:   current.FetchedAt,
+		FromHash:      previous.Hash,
+		ToHash:        current.Hash,
+		Days:          []model.PredictionDayDiff{},
+	}
+	dates := make([]string, 0, len(currDays))
+	for date := range currDays {
+		dates = append(dates, date)
+	}
+	for date := range prevDays {
+		if _, ok := currDays[date]; !ok {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		prev, hadPrev := prevDays[date]
+		curr, hasCurr := currDays[date]
+		day := model.PredictionDayDiff{Date: date, Fields: map[string]model.PredictionFieldChange{}}
+		switch {
+		case !hadPrev:
+			day.Status = DiffAdded
+		case !hasCurr:
+			day.Status = DiffRemoved
+		default:
+			day.Status = DiffChanged
+		}
+		day.PreviousAmount, day.PreviousLevel = amountAndLevel(prev)
+		day.CurrentAmount, day.Level = amountAndLevel(curr)
+		if day.PreviousAmount != nil && day.CurrentAmount != nil {
+			delta := math.Round((*day.CurrentAmount-*day.PreviousAmount)*1000) / 1000
+			day.AmountDelta = &delta
+		}
+
+		if day.Status == DiffChanged {
+			for key, value := range curr {
+				if key == "date" || key == "predicted_amount" {
+					continue
+				}
+				if old := prev[key]; !reflect.DeepEqual(old, value) {
+					day.Fields[key] = model.PredictionFieldChange{Previous: old, Current: value}
+				}
+			}
+			for key, old := range prev {
+				if _, ok := curr[key]; !ok && key != "date" && key != "predicted_amount" {
+					day.Fields[key] = model.PredictionFieldChange{Previous: old, Current: nil}
+				}
+			}
+			amountChanged := !reflect.DeepEqual(prev["predicted_amount"], curr["predicted_amount"])
+			if !amountChanged && len(day.Fields) == 0 {
+				continue
+			}
+		}
+		diff.Days = append(diff.Days, day)
+	}
+	return diff, nil
+}
+
+// MaterialDays は差分のうち大きな変化があった日を日付順に返す
+// 大きな変化とは、レベルが変わったか、予測値がMaterialDelta以上変わったこと
+// 新しく予測の対象になった日はプチ湧き以上のときだけ含め、予測の対象から外れた日は含めない
+func MaterialDays(diff *model.PredictionDiff) []model.PredictionDayDiff {
+	days := []model.PredictionDayDiff{}
+	for _, d := range diff.Days {
+		if d.Level == nil {
+			continue
+		}
+		switch d.Status {
+		case DiffAdded:
+			if *d.Level >= 1 {
+				days = append(days, d)
+			}
+		case DiffChanged:
+			if d.PreviousLevel == nil {
+				continue
+			}
+			if *d.Level != *d.PreviousLevel || (d.AmountDelta != nil && math.Abs(*d.AmountDelta) >= MaterialDelta) {
+				days = append(days, d)
+			}
+		}
+	}
+	return days
+}
+
+// ForecastDay は予測データ1日分の予測値とレベル
+type ForecastDay struct {
+	Date   string
+	Amount float64
+	Level  int
+}
+
+// ForecastDays は予測データの各日の予測値を日付順に返す（予測値のない日は除く）
+func ForecastDays(data []byte) ([]ForecastDay, error) {
+	parsed, err := parsePredictionDays(data)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]ForecastDay, 0, len(parsed))
+	for date, d := range parsed {
+		if amount, lvl := amountAndLevel(d); amount != nil {
+			days = append(days, ForecastDay{Date: date, Amount: *amount, Level: *lvl})
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days, nil
+}
+
+// parsePredictionDays は予測データを日付ごとの項目に分ける
+func parsePredictionDays(data []byte) (map[string]map[string]interface{}, error) {
+	var days []map[string]interface{}
+	if err := json.Unmarshal(data, &days); err != nil {
+		return nil, err
+	}
+	result := make(map[string]map[string]interface{}, len(days))
+	for _, d := range days {
+		if date, ok := d["date"].(string); ok {
+			result[date] = d
+		}
+	}
+	return result, nil
+}
+
+func amountAndLevel(day map[string]interface{}) (*float64, *int) {
+	amount, ok := day["predicted_amount"].(float64)
+	if !ok {
+		return nil, nil
+	}
+	lvl := level.FromAmount(amount)
+	return &amount, &lvl
+}
+
+// recordPredictionDiff は差分を保持し、古いものから捨てる
+func (c *CacheManager) recordPredictionDiff(diff model.PredictionDiff) {
+	c.predictionDiffs.Lock()
+	defer c.predictionDiffs.Unlock()
+	c.predictionDiffs.items = append(c.predictionDiffs.items, diff)
+	if n := len(c.predictionDiffs.items); n > c.diffHistory {
+		c.predictionDiffs.items = append([]model.PredictionDiff(nil), c.predictionDiffs.items[n-c.diffHistory:]...)
+	}
+}
+
+// PredictionDiffs は保持している予測データの差分を新しい順に返す（limitが0以下の場合は全件）
+func (c *CacheManager) PredictionDiffs(limit int) []model.PredictionDiff {
+	c.predictionDiffs.RLock()
+	defer c.predictionDiffs.RUnlock()
+	items := c.predictionDiffs.items
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+	result := make([]model.PredictionDiff, 0, limit)
+	for i := len(items) - 1; i >= len(items)-limit; i-- {
+		result = append(result, items[i])
+	}
+	return result
+}
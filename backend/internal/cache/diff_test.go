@@ -0,0 +1,159 @@
+// backend/internal/cache/diff_test.go
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+func TestDiffPredictions(t *testing.T) {
+	previous := newEntry([]byte(`[
+		{"date": "2026-04-01", "predicted_amount": 0.3, "weather_code": 3, "moon_age": 13.2},
+		{"date": "2026-04-02", "predicted_amount": 0.8, "weather_code": 61, "moon_age": 14.2},
+		{"date": "2026-04-03", "predicted_amount": 1.0, "weather_code": 1, "moon_age": 15.2}
+	]`), time.Unix(1775000000, 0))
+	current := newEntry([]byte(`[
+		{"date": "2026-04-02", "predicted_amount": 1.2, "weather_code": 3, "moon_age": 14.2},
+		{"date": "2026-04-03", "predicted_amount": 1.0, "weather_code": 1, "moon_age": 15.2},
+		{"date": "2026-04-04", "predicted_amount": 0.5, "weather_code": 0, "moon_age": 16.2}
+	]`), time.Unix(1775003600, 0))
+
+	diff, err := DiffPredictions(previous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.FromHash != previous.Hash || diff.ToHash != current.Hash {
+		t.Errorf("hash = %s → %s", diff.FromHash, diff.ToHash)
+	}
+	if len(diff.Days) != 3 {
+		t.Fatalf("days = %+v", diff.Days)
+	}
+
+	removed, changed, added := diff.Days[0], diff.Days[1], diff.Days[2]
+	if removed.Date != "2026-04-01" || removed.Status != DiffRemoved || removed.CurrentAmount != nil || *removed.PreviousAmount != 0.3 {
+		t.Errorf("removed = %+v", removed)
+	}
+	if changed.Date != "2026-04-02" || changed.Status != DiffChanged {
+		t.Errorf("changed = %+v", changed)
+	}
+	if *changed.AmountDelta != 0.4 || *changed.PreviousLevel != 2 || *changed.Level != 4 {
+		t.Errorf("amount = %v → %v (%v), level = %v → %v", *changed.PreviousAmount, *changed.CurrentAmount, *changed.AmountDelta, *changed.PreviousLevel, *changed.Level)
+	}
+	if len(changed.Fields) != 1 || changed.Fields["weather_code"] != (model.PredictionFieldChange{Previous: 61.0, Current: 3.0}) {
+		t.Errorf("fields = %+v", changed.Fields)
+	}
+	if added.Date != "2026-04-04" || added.Status != DiffAdded || added.PreviousAmount != nil || added.AmountDelta != nil || *added.Level != 1 {
+		t.Errorf("added = %+v", added)
+	}
+}
+
+// TestMaterialDays は差分のうち大きな変化があった日だけを選ぶことをテストする
+func TestMaterialDays(t *testing.T) {
+	base := time.Date(2026, 4, 10, 6, 0, 0, 0, jst)
+	material := func(previous, current string) []model.PredictionDayDiff {
+		t.Helper()
+		diff, err := DiffPredictions(newEntry([]byte(previous), base), newEntry([]byte(current), base.Add(time.Hour)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return MaterialDays(diff)
+	}
+
+	// 小さな変化だけ（レベルも変わらない）
+	if days := material(`[{"date":"2026-04-10","predicted_amount":0.5},{"date":"2026-04-11","predicted_amount":1.0}]`,
+		`[{"date":"2026-04-10","predicted_amount":0.55},{"date":"2026-04-11","predicted_amount":1.05}]`); len(days) != 0 {
+		t.Errorf("小さな変化が含まれています: %+v", days)
+	}
+
+	// 4/11が湧き→大湧き、4/12が湧きで新しく追加、4/09が予測の対象から外れた
+	days := material(`[{"date":"2026-04-09","predicted_amount":1.3},{"date":"2026-04-10","predicted_amount":0.55},{"date":"2026-04-11","predicted_amount":1.05}]`,
+		`[{"date":"2026-04-10","predicted_amount":0.6},{"date":"2026-04-11","predicted_amount":1.2},{"date":"2026-04-12","predicted_amount":0.95}]`)
+	if len(days) != 2 {
+		t.Fatalf("days = %+v", days)
+	}
+	if d := days[0]; d.Date != "2026-04-11" || *d.PreviousLevel != 3 || *d.Level != 4 || *d.PreviousAmount != 1.05 {
+		t.Errorf("days[0] = %+v", d)
+	}
+	if d := days[1]; d.Date != "2026-04-12" || d.Status != DiffAdded || *d.Level != 3 {
+		t.Errorf("days[1] = %+v", d)
+	}
+
+	// レベルは同じだが大きく下がった
+	days = material(`[{"date":"2026-04-10","predicted_amount":0.6}]`, `[{"date":"2026-04-10","predicted_amount":0.4}]`)
+	if len(days) != 1 || days[0].Date != "2026-04-10" {
+		t.Errorf("days = %+v", days)
+	}
+}
+
+// TestForecastDays は予測データの各日の予測値を日付順に返すことをテストする
+func TestForecastDays(t *testing.T) {
+	days, err := ForecastDays([]byte(`[{"date":"2026-04-11","predicted_amount":1.2},{"date":"2026-04-10","predicted_amount":0.2},{"date":"2026-04-12"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 || days[0].Date != "2026-04-10" || days[1].Level != 4 {
+		t.Errorf("days = %+v", days)
+	}
+	if _, err := ForecastDays([]byte(`{"error":"x"}`)); err == nil {
+		t.Error("不正な予測データがエラーになりませんでした")
+	}
+}
+
+// sequencePredictionProvider は呼び出しごとに順番に予測データを返す
+type sequencePredictionProvider struct {
+	payloads [][]byte
+}
+
+func (p *sequencePredictionProvider) Name() string { return "sequence" }
+
+func (p *sequencePredictionProvider) FetchPrediction() ([]byte, error) {
+	body := p.payloads[0]
+	p.payloads = p.payloads[1:]
+	return body, nil
+}
+
+func TestFetchAndCachePredictionDataRecordsDiffs(t *testing.T) {
+	payloads := [][]byte{
+		[]byte(`[{"date": "2026-04-02", "predicted_amount": 0.3}]`),
+		[]byte(`[{"date": "2026-04-02", "predicted_amount": 0.3}]`),
+		[]byte(`[{"date": "2026-04-02", "predicted_amount": 0.7}]`),
+	}
+	cm := newFixtureCacheManager(t)
+	cm.providers.Prediction = &sequencePredictionProvider{payloads: payloads}
+	var updates []PredictionUpdate
+	cm.AddPredictionListener(func(update PredictionUpdate) {
+		updates = append(updates, update)
+	})
+	for i := 0; i < 3; i++ {
+		if err := cm.FetchAndCachePredictionData(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(updates) != 3 || updates[0].Diff != nil || updates[1].Diff != nil || updates[2].Diff == nil {
+		t.Fatalf("updates = %+v", updates)
+	}
+	diffs := cm.PredictionDiffs(0)
+	if len(diffs) != 1 || diffs[0].Days[0].Date != "2026-04-02" || *diffs[0].Days[0].AmountDelta != 0.4 {
+		t.Errorf("diffs = %+v", diffs)
+	}
+}
+
+func TestPredictionDiffsKeepsNewest(t *testing.T) {
+	cm := newFixtureCacheManager(t)
+	for i := 0; i < maxPredictionDiffs+5; i++ {
+		cm.recordPredictionDiff(model.PredictionDiff{ToHash: string(rune('a' + i))})
+	}
+	diffs := cm.PredictionDiffs(0)
+	if len(diffs) != maxPredictionDiffs {
+		t.Fatalf("len = %d", len(diffs))
+	}
+	if diffs[0].ToHash != string(rune('a'+maxPredictionDiffs+4)) || diffs[len(diffs)-1].ToHash != string(rune('a'+5)) {
+		t.Errorf("order = %s ... %s", diffs[0].ToHash, diffs[len(diffs)-1].ToHash)
+	}
+	if got := cm.PredictionDiffs(3); len(got) != 3 || got[0].ToHash != diffs[0].ToHash {
+		t.Errorf("limit 3 = %+v", got)
+	}
+}
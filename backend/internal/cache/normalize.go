@@ -0,0 +1,281 @@
+// backend/internal/cache/normalize.go
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/astronomy"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// 外部APIのレスポンスのうち、型付きの詳細データに変換するフィールド
+
+type rawDetail struct {
+	Spot     model.DetailSpot   `json:"spot"`
+	Weather  *openMeteoResponse `json:"weather"`
+	Tide     *tide736Response   `json:"tide"`
+	NextTide *tide736Response   `json:"nextTide"`
+}
+
+type openMeteoResponse struct {
+	Hourly *struct {
+		Time                     []string   `json:"time"`
+		Temperature              []*float64 `json:"temperature_2m"`
+		Precipitation            []*float64 `json:"precipitation"`
+		PrecipitationProbability []*float64 `json:"precipitation_probability"`
+		WeatherCode              []*float64 `json:"weather_code"`
+		WindSpeed                []*float64 `json:"wind_speed_10m"`
+		WindDirection            []*float64 `json:"wind_direction_10m"`
+	} `json:"hourly"`
+}
+
+type tide736Response struct {
+	Source string `json:"source"` // 調和定数による推算値の場合は"computed"（tide736.netのレスポンスにはない）
+	Tide   struct {
+		Chart map[string]tide736Day `json:"chart"`
+	} `json:"tide"`
+}
+
+type tide736Day struct {
+	Moon struct {
+		Age   json.Number  `json:"age"` // "22.5" のように文字列で返ってくる
+		Title string       `json:"title"`
+		Illum *json.Number `json:"illum"`
+	} `json:"moon"`
+	Sun struct {
+		Rise string `json:"rise"`
+		Set  string `json:"set"`
+	} `json:"sun"`
+	Flood []tide736Point `json:"flood"`
+	Ebb   []tide736Point `json:"edd"`
+	Tide  []tide736Point `json:"tide"`
+}
+
+type tide736Point struct {
+	Time string      `json:"time"`
+	Cm   json.Number `json:"cm"`
+}
+
+// normalizeDetail はキャッシュ用の詳細データ（外部APIのレスポンスをまとめたもの）を型付きの形式に変換する
+// 必要なフィールドが欠けている・値が不正な場合はエラーを返す
+func normalizeDetail(data []byte, dateStr string, fetchedAt time.Time) (*model.DetailData, error) {
+	var raw rawDetail
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("詳細データの形式が不正です: %w", err)
+	}
+	if raw.Spot.ID == "" {
+		return nil, fmt.Errorf("地点情報がありません")
+	}
+	weather, err := normalizeWeather(raw.Weather)
+	if err != nil {
+		return nil, fmt.Errorf("気象データの変換に失敗しました: %w", err)
+	}
+	if raw.Tide == nil {
+		return nil, fmt.Errorf("潮汐データがありません")
+	}
+	tide, err := normalizeTide(raw.Tide, dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("潮汐データの変換に失敗しました: %w", err)
+	}
+	fillAstronomy(tide, raw.Spot)
+	detail := &model.DetailData{
+		SchemaVersion: model.DetailSchemaVersion,
+		Date:          dateStr,
+		Spot:          raw.Spot,
+		FetchedAt:     fetchedAt.In(jst),
+		Weather:       weather,
+		Tide:          *tide,
+	}
+	if raw.NextTide != nil {
+		date, err := time.ParseInLocation("2006-01-02", dateStr, jst)
+		if err != nil {
+			return nil, fmt.Errorf("日付の形式が不正です: %w", err)
+		}
+		nextTide, err := normalizeTide(raw.NextTide, date.AddDate(0, 0, 1).Format("2006-01-02"))
+		if err != nil {
+			return nil, fmt.Errorf("翌日の潮汐データの変換に失敗しました: %w", err)
+		}
+		fillAstronomy(nextTide, raw.Spot)
+		detail.NextTide = nextTide
+	}
+	return detail, nil
+}
+
+// normalizeWeather はOpen-Meteoの時間別データを1時間ごとの配列に変換する
+func normalizeWeather(resp *openMeteoResponse) ([]model.HourlyWeather, error) {
+	if resp == nil || resp.Hourly == nil {
+		return nil, fmt.Errorf("hourlyがありません")
+	}
+	h := resp.Hourly
+	if len(h.Time) == 0 {
+		return nil, fmt.Errorf("hourly.timeが空です")
+	}
+	columns := []struct {
+		name   string
+		values []*float64
+	}{
+		{"temperature_2m", h.Temperature},
+		{"precipitation", h.Precipitation},
+		{"precipitation_probability", h.PrecipitationProbability},
+		{"weather_code", h.WeatherCode},
+		{"wind_speed_10m", h.WindSpeed},
+		{"wind_direction_10m", h.WindDirection},
+	}
+	for _, col := range columns {
+		if len(col.values) != len(h.Time) {
+			return nil, fmt.Errorf("hourly.%sの要素数(%d)がtimeの要素数(%d)と一致しません", col.name, len(col.values), len(h.Time))
+		}
+		for i, v := range col.values {
+			if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+				return nil, fmt.Errorf("hourly.%s[%d]の値が不正です", col.name, i)
+			}
+		}
+	}
+
+	hours := make([]model.HourlyWeather, len(h.Time))
+	for i, ts := range h.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", ts, jst)
+		if err != nil {
+			return nil, fmt.Errorf("hourly.time[%d]の形式が不正です: %q", i, ts)
+		}
+		if i > 0 && !t.After(hours[i-1].Time) {
+			return nil, fmt.Errorf("hourly.time[%d]が時刻順になっていません: %q", i, ts)
+		}
+		hours[i] = model.HourlyWeather{
+			Time:                     t,
+			Temperature:              *h.Temperature[i],
+			Precipitation:            *h.Precipitation[i],
+			PrecipitationProbability: *h.PrecipitationProbability[i],
+			WeatherCode:              int(*h.WeatherCode[i]),
+			WindSpeed:                *h.WindSpeed[i],
+			WindDirection:            *h.WindDirection[i],
+		}
+	}
+	return hours, nil
+}
+
+// normalizeTide はtide736.netのレスポンスから指定日の潮汐データを取り出して変換する
+func normalizeTide(resp *tide736Response, dateStr string) (*model.TideDay, error) {
+	day, ok := resp.Tide.Chart[dateStr]
+	if !ok {
+		return nil, fmt.Errorf("%sのデータがありません", dateStr)
+	}
+	date, err := time.ParseInLocation("2006-01-02", dateStr, jst)
+	if err != nil {
+		return nil, fmt.Errorf("日付の形式が不正です: %w", err)
+	}
+
+	source := "tide736"
+	if resp.Source != "" {
+		source = resp.Source
+	}
+	tide := &model.TideDay{
+		Date:     dateStr,
+		Source:   source,
+		TideName: day.Moon.Title,
+		Events:   []model.TideEvent{},
+	}
+	if day.Moon.Age != "" {
+		age, err := day.Moon.Age.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("月齢の形式が不正です: %q", day.Moon.Age)
+		}
+		tide.Moon.Age = &age
+	}
+	if day.Moon.Illum != nil {
+		illum, err := day.Moon.Illum.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("輝面比の形式が不正です: %q", *day.Moon.Illum)
+		}
+		tide.Moon.Illumination = &illum
+	}
+	// 日の出・日の入りがない日（"--:--"など）はnullにする
+	if t, err := parseClock(date, day.Sun.Rise); err == nil {
+		tide.Sun.Rise = &t
+	}
+	if t, err := parseClock(date, day.Sun.Set); err == nil {
+		tide.Sun.Set = &t
+	}
+
+	for _, kind := range []struct {
+		typ    string
+		points []tide736Point
+	}{{model.TideFlood, day.Flood}, {model.TideEbb, day.Ebb}} {
+		for i, p := range kind.points {
+			t, height, err := parseTidePoint(date, p)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", kind.typ, i, err)
+			}
+			tide.Events = append(tide.Events, model.TideEvent{Type: kind.typ, Time: t, Height: height})
+		}
+	}
+	sort.Slice(tide.Events, func(i, j int) bool { return tide.Events[i].Time.Before(tide.Events[j].Time) })
+
+	if len(day.Tide) == 0 {
+		return nil, fmt.Errorf("潮位曲線が空です")
+	}
+	tide.Curve = make([]model.TidePoint, len(day.Tide))
+	for i, p := range day.Tide {
+		t, height, err := parseTidePoint(date, p)
+		if err != nil {
+			return nil, fmt.Errorf("tide[%d]: %w", i, err)
+		}
+		if i > 0 && !t.After(tide.Curve[i-1].Time) {
+			return nil, fmt.Errorf("tide[%d]が時刻順になっていません: %q", i, p.Time)
+		}
+		tide.Curve[i] = model.TidePoint{Time: t, Height: height}
+	}
+	return tide, nil
+}
+
+// fillAstronomy は潮汐データにない月と太陽の情報を地点の座標から計算して補う
+func fillAstronomy(tide *model.TideDay, s model.DetailSpot) {
+	date, err := time.ParseInLocation("2006-01-02", tide.Date, jst)
+	if err != nil {
+		return
+	}
+	astro := astronomy.Day(s.Latitude, s.Longitude, date)
+	if tide.Moon.Age == nil {
+		tide.Moon.Age = &astro.MoonAge
+	}
+	if tide.Moon.Illumination == nil {
+		tide.Moon.Illumination = &astro.MoonIllumination
+	}
+	tide.Moon.Phase = astronomy.PhaseName(*tide.Moon.Age)
+	tide.Moon.Rise, tide.Moon.Set = astro.Moonrise, astro.Moonset
+	if tide.Sun.Rise == nil {
+		tide.Sun.Rise = astro.Sunrise
+	}
+	if tide.Sun.Set == nil {
+		tide.Sun.Set = astro.Sunset
+	}
+}
+
+func parseTidePoint(date time.Time, p tide736Point) (time.Time, float64, error) {
+	t, err := parseClock(date, p.Time)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	height, err := p.Cm.Float64()
+	if err != nil || math.IsNaN(height) || math.IsInf(height, 0) {
+		return time.Time{}, 0, fmt.Errorf("潮位の値が不正です: %q", p.Cm)
+	}
+	return t, height, nil
+}
+
+// parseClock は"HH:MM"形式の時刻を指定日の時刻に変換する（"24:00"は翌日0時として扱う）
+func parseClock(date time.Time, clock string) (time.Time, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(strings.TrimSpace(clock), "%d:%d", &hour, &minute); err != nil {
+		return time.Time{}, fmt.Errorf("時刻の形式が不正です: %q", clock)
+	}
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return time.Time{}, fmt.Errorf("時刻の範囲が不正です: %q", clock)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, jst), nil
+}
@@ -0,0 +1,222 @@
+// backend/internal/cache/snapshot.go
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Snapshot はキャッシュ全体を保存・復元するための形式
+type Snapshot struct {
+	SavedAt    time.Time                 `json:"saved_at"`
+	Prediction *snapshotEntry            `json:"prediction,omitempty"`
+	Details    map[string]*snapshotEntry `json:"details"`
+}
+
+// Bodyは取得時のデータそのもの（JSONではBase64）。json.RawMessageのままだと保存時に空白の除去や
+// HTMLのエスケープでバイト列が変わり、復元したデータと配信するETagが食い違うため、そのまま保存する
+// Dataは以前の形式のスナップショットを読み込むためだけに残している
+type snapshotEntry struct {
+	Body      []byte          `json:"body,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	FetchedAt time.Time       `json:"fetched_at"`
+}
+
+// bytes は保存したデータを返す（以前の形式ではData）
+func (e *snapshotEntry) bytes() []byte {
+	if e.Body != nil {
+		return e.Body
+	}
+	return e.Data
+}
+
+// restore はスナップショットの項目を古いもの（Stale）としてキャッシュのエントリに戻す
+// ハッシュは復元したデータから計算し直すので、ETagは常に配信するバイト列と一致する
+func (e *snapshotEntry) restore() *Entry {
+	entry := newEntry(e.bytes(), e.FetchedAt)
+	entry.Stale = true
+	return entry
+}
+
+// SnapshotStore はスナップショットの保存先
+type SnapshotStore interface {
+	Save(snapshot *Snapshot) error
+	// Load は保存済みのスナップショットを返す。まだ保存されていない場合は(nil, nil)を返す
+	Load() (*Snapshot, error)
+}
+
+// SetSnapshotStore はスナップショットの保存先を設定する
+func (c *CacheManager) SetSnapshotStore(store SnapshotStore) {
+	c.snapshotStore = store
+}
+
+// SaveSnapshot は現在のキャッシュをスナップショットとして保存する
+func (c *CacheManager) SaveSnapshot() error {
+	if c.snapshotStore == nil {
+		return nil
+	}
+	snapshot := &Snapshot{SavedAt: time.Now(), Details: make(map[string]*snapshotEntry)}
+	c.predictionCache.RLock()
+	if e := c.predictionCache.entry; e != nil {
+		snapshot.Prediction = &snapshotEntry{Body: e.Data, FetchedAt: e.FetchedAt}
+	}
+	c.predictionCache.RUnlock()
+	c.detailCache.RLock()
+	for key, e := range c.detailCache.data {
+		snapshot.Details[key] = &snapshotEntry{Body: e.Data, FetchedAt: e.FetchedAt}
+	}
+	c.detailCache.RUnlock()
+
+	if err := c.snapshotStore.Save(snapshot); err != nil {
+		return fmt.Errorf("スナップショットの保存に失敗しました: %w", err)
+	}
+	c.logger.Info("キャッシュのスナップショットを保存しました", "details", len(snapshot.Details))
+	return nil
+}
+
+// LoadSnapshot は保存済みのスナップショットをキャッシュに読み込む
+// 読み込んだデータは新しいデータを取得するまで古いもの（Stale）として扱う
+// 昨日より前の詳細データは表示対象外なので読み込まない
+func (c *CacheManager) LoadSnapshot() error {
+	if c.snapshotStore == nil {
+		return nil
+	}
+	snapshot, err := c.snapshotStore.Load()
+	if err != nil {
+		return fmt.Errorf("スナップショットの読み込みに失敗しました: %w", err)
+	}
+	if snapshot == nil {
+		c.logger.Info("キャッシュのスナップショットはまだありません")
+		return nil
+	}
+
+	oldest := time.Now().In(jst).AddDate(0, 0, -1).Format("2006-01-02")
+	loaded := 0
+	c.predictionCache.Lock()
+	if snapshot.Prediction != nil && c.predictionCache.entry == nil {
+		c.predictionCache.entry = snapshot.Prediction.restore()
+	}
+	c.predictionCache.Unlock()
+	c.detailCache.Lock()
+	for key, e := range snapshot.Details {
+		date := key[strings.LastIndex(key, ":")+1:]
+		if date < oldest {
+			continue
+		}
+		if _, exists := c.detailCache.data[key]; exists {
+			continue
+		}
+		c.detailCache.data[key] = e.restore()
+		loaded++
+
+		// 型付きの形式はスナップショットに含めず、元データから変換し直す
+		detail, err := normalizeDetail(e.bytes(), date, e.FetchedAt)
+		if err != nil {
+			c.logger.Warn("スナップショットの詳細データを型付きの形式に変換できませんでした", "key", key, "error", err)
+			continue
+		}
+		typed, err := newTypedDetail(detail, e.FetchedAt)
+		if err != nil {
+			c.logger.Warn("スナップショットの詳細データを型付きの形式に変換できませんでした", "key", key, "error", err)
+			continue
+		}
+		typed.entry.Stale = true
+		c.detailCache.typed[key] = typed
+	}
+	c.detailCache.Unlock()
+	c.logger.Info("キャッシュのスナップショットを読み込みました", "saved_at", snapshot.SavedAt, "details", loaded)
+	return nil
+}
+
+// FileSnapshotStore はスナップショットをローカルファイルに保存する
+type FileSnapshotStore struct {
+	path string
+}
+
+// NewFileSnapshotStore は新しいFileSnapshotStoreを初期化する
+func NewFileSnapshotStore(path string) *FileSnapshotStore {
+	return &FileSnapshotStore{path: path}
+}
+
+func (f *FileSnapshotStore) Save(snapshot *Snapshot) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	// 書き込み途中のファイルを読まないよう、一時ファイルに書いてからリネームする
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".cache-snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f *FileSnapshotStore) Load() (*Snapshot, error) {
+	body, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(body, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// DBSnapshotStore はスナップショットをPostgresに保存する
+// Cloud Runのようにインスタンスのディスクが残らない環境向け
+// JSONBは空白やキーの順序を変えてしまうので、payloadはBYTEAのまま保存する（各データもBase64で元のバイト列のまま持つ）
+type DBSnapshotStore struct {
+	db *sql.DB
+}
+
+// NewDBSnapshotStore は新しいDBSnapshotStoreを初期化する
+func NewDBSnapshotStore(db *sql.DB) *DBSnapshotStore {
+	return &DBSnapshotStore{db: db}
+}
+
+const dbSnapshotName = "forecast"
+
+func (d *DBSnapshotStore) Save(snapshot *Snapshot) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT INTO cache_snapshots (name, payload, saved_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET payload = EXCLUDED.payload, saved_at = EXCLUDED.saved_at`,
+		dbSnapshotName, body, snapshot.SavedAt)
+	return err
+}
+
+func (d *DBSnapshotStore) Load() (*Snapshot, error) {
+	var body []byte
+	err := d.db.QueryRow(`SELECT payload FROM cache_snapshots WHERE name = $1`, dbSnapshotName).Scan(&body)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(body, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,110 @@
+// backend/internal/compress/compress_test.go
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                           "",
+		"gzip, deflate, br":          Brotli,
+		"gzip":                       Gzip,
+		"GZIP;q=0.8, br;q=0.5":       Gzip,
+		"br;q=0, gzip;q=0":           "",
+		"identity":                   "",
+		"*":                          Brotli,
+		"*;q=0.5, br;q=0":            Gzip,
+		"deflate, gzip;q=1.0, *;q=0": Gzip,
+	}
+	for header, want := range cases {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func serve(handler http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	rec := httptest.NewRecorder()
+	Middleware(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareCompressesLargeJSON(t *testing.T) {
+	body := `[` + strings.Repeat(`{"content":"ホタルイカが湧いています"},`, 200) + `{}]`
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		// 小さく分けて書いても1つの圧縮ストリームになること
+		for i := 0; i < len(body); i += 100 {
+			io.WriteString(w, body[i:min(i+100, len(body))])
+		}
+	}
+
+	rec := serve(handler, "gzip")
+	if rec.Code != http.StatusCreated || rec.Header().Get("Content-Encoding") != Gzip || rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("status = %d, header = %v", rec.Code, rec.Header())
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(zr); string(got) != body {
+		t.Errorf("gzipの展開結果が一致しません")
+	}
+
+	rec = serve(handler, "br, gzip")
+	if rec.Header().Get("Content-Encoding") != Brotli {
+		t.Fatalf("header = %v", rec.Header())
+	}
+	if got, _ := io.ReadAll(brotli.NewReader(rec.Body)); string(got) != body {
+		t.Errorf("brotliの展開結果が一致しません")
+	}
+}
+
+func TestMiddlewarePassesThrough(t *testing.T) {
+	large := bytes.Repeat([]byte{0xff, 0xd8, 0xff, 0xe0}, 1000)
+	cases := map[string]http.HandlerFunc{
+		"小さいレスポンス": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"ok":true}`)
+		},
+		"画像": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write(large)
+		},
+		"圧縮済み": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", Gzip)
+			w.Write(large)
+		},
+		"304": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		},
+	}
+	for name, handler := range cases {
+		rec := serve(handler, "br, gzip")
+		want := ""
+		if name == "圧縮済み" {
+			want = Gzip
+		}
+		if got := rec.Header().Get("Content-Encoding"); got != want {
+			t.Errorf("%s: Content-Encoding = %q, want %q", name, got, want)
+		}
+	}
+
+	rec := serve(cases["画像"], "br")
+	if !bytes.Equal(rec.Body.Bytes(), large) {
+		t.Error("圧縮しないレスポンスの本文が変わっています")
+	}
+}
@@ -0,0 +1,178 @@
+// backend/internal/compress/middleware.go
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// これより小さいレスポンスは圧縮しても効果が薄いのでそのまま返す
+const minSize = 1024
+
+// リクエストごとに圧縮するときの圧縮レベル（CPU負荷を抑えるため控えめにする）
+const (
+	dynamicGzipLevel   = gzip.DefaultCompression
+	dynamicBrotliLevel = 4
+)
+
+// 圧縮するContent-Type（画像などすでに圧縮されている形式は除く）
+var compressibleTypes = []string{
+	"application/json",
+	"application/xml",
+	"application/atom+xml",
+	"application/rss+xml",
+	"application/javascript",
+	"image/svg+xml",
+	"text/",
+}
+
+// Middleware はAccept-Encodingに応じてレスポンスをbrotliまたはgzipで圧縮する
+// ハンドラが自分でContent-Encodingを設定した場合（圧縮済みのキャッシュを返す場合など）はそのまま返す
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := Negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// compressWriter はレスポンスの先頭minSizeバイトを溜めてから圧縮するかどうかを決める
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	enc         io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if code < 200 {
+		// 1xxの情報レスポンスはそのまま送る
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	cw.wroteHeader = true
+	if !cw.decided {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) < minSize {
+			return len(p), nil
+		}
+		pending := cw.buf
+		cw.buf = nil
+		if err := cw.start(pending, true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// decide は溜めたデータを圧縮するかどうかを決めて書き出す
+func (cw *compressWriter) decide(compress bool) error {
+	pending := cw.buf
+	cw.buf = nil
+	return cw.start(pending, compress)
+}
+
+func (cw *compressWriter) start(pending []byte, compress bool) error {
+	cw.decided = true
+	h := cw.Header()
+	if h.Get("Content-Type") == "" && len(pending) > 0 {
+		h.Set("Content-Type", http.DetectContentType(pending))
+	}
+	if compress && h.Get("Content-Encoding") == "" && cw.status != http.StatusNoContent &&
+		cw.status != http.StatusNotModified && compressible(h.Get("Content-Type")) {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.encoding)
+		switch cw.encoding {
+		case Brotli:
+			cw.enc = brotli.NewWriterLevel(cw.ResponseWriter, dynamicBrotliLevel)
+		default:
+			cw.enc, _ = gzip.NewWriterLevel(cw.ResponseWriter, dynamicGzipLevel)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(pending) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(pending)
+	} else {
+		_, err = cw.ResponseWriter.Write(pending)
+	}
+	return err
+}
+
+// Flush は溜めているデータを圧縮して送り出す（ストリーミングのレスポンス用）
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.decide(true)
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close は残りのデータを書き出して圧縮を終える
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// ハンドラが何も書かなかった場合はnet/httpに任せる
+			cw.decided = true
+			return nil
+		}
+		// minSizeに満たなかったので圧縮しない
+		if err := cw.decide(false); err != nil {
+			return err
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Close()
+	}
+	return nil
+}
+
+// Unwrap はhttp.ResponseControllerから元のResponseWriterを使えるようにする
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func compressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, t := range compressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,512 @@
+// backend/internal/config/config.go
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/push"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scheduler"
+	"gopkg.in/yaml.v3"
+)
+
+// Config はサーバーの設定
+// 設定ファイル（YAML）を読み込んだ後、環境変数が設定されている項目を環境変数の値で上書きする
+type Config struct {
+	Server   Server   `yaml:"server"`
+	Log      Log      `yaml:"log"`
+	Database Database `yaml:"database"`
+	Auth     Auth     `yaml:"auth"`
+	Data     Data     `yaml:"data"`
+	Cache    Cache    `yaml:"cache"`
+	Refresh  Refresh  `yaml:"refresh"`
+	Night    Night    `yaml:"night"`
+	Forecast Forecast `yaml:"forecast"`
+	Storage  Storage  `yaml:"storage"`
+	Push     Push     `yaml:"push"`
+}
+
+// Server はHTTPサーバーの設定
+type Server struct {
+	Port            string        `yaml:"port"`             // PORT
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // SHUTDOWN_TIMEOUT（Cloud RunはSIGTERMの10秒後に強制終了するので、それより短くする）
+	AllowedOrigins  []string      `yaml:"allowed_origins"`  // ALLOWED_ORIGINS（カンマ区切り）
+	SiteURL         string        `yaml:"site_url"`         // SITE_URL（カレンダーなどに載せるリンクの先）
+	MetricsToken    string        `yaml:"metrics_token"`    // METRICS_TOKEN（/metricsに必要なBearerトークン。未設定の場合は/metricsを公開しない）
+}
+
+// Log はログの設定
+type Log struct {
+	Level string `yaml:"level"` // LOG_LEVEL（debug, info, warn, error のいずれか）
+}
+
+// Database はデータベースの設定
+type Database struct {
+	URL string `yaml:"url"` // DATABASE_URL
+}
+
+// Auth は管理人機能・定期実行・表示用IDの秘密の値
+type Auth struct {
+	JWTSecret     string `yaml:"jwt_secret"`      // JWT_SECRET_KEY
+	AdminPassword string `yaml:"admin_password"`  // ADMIN_PASSWORD（未設定の場合は管理人機能が無効）
+	CronSecret    string `yaml:"cron_secret"`     // CRON_SECRET_KEY（未設定の場合は/api/tasks/が無効）
+	DisplayIDSalt string `yaml:"display_id_salt"` // DISPLAY_ID_SALT
+}
+
+// Data はデータ取得元の設定
+type Data struct {
+	Source           string `yaml:"source"`             // DATA_SOURCE（live または fixture）
+	PredictionAPIURL string `yaml:"prediction_api_url"` // PREDICTION_API_URL（liveの場合は必須）
+	FixtureDir       string `yaml:"fixture_dir"`        // FIXTURE_DIR
+	TideStationsPath string `yaml:"tide_stations_path"` // TIDE_STATIONS_PATH（未設定の場合は推算による代替なし）
+	IndexConfigPath  string `yaml:"index_config_path"`  // INDEX_CONFIG_PATH（未設定の場合は組み込みの指数設定）
+}
+
+// Cache はキャッシュの設定
+type Cache struct {
+	Snapshot        string        `yaml:"snapshot"`         // CACHE_SNAPSHOT（db, file, off のいずれか）
+	SnapshotPath    string        `yaml:"snapshot_path"`    // CACHE_SNAPSHOT_PATH（fileの場合の保存先）
+	RequestInterval time.Duration `yaml:"request_interval"` // CACHE_REQUEST_INTERVAL
+	DiffHistory     int           `yaml:"diff_history"`     // CACHE_DIFF_HISTORY
+}
+
+// Refresh はキャッシュの定期更新の設定
+type Refresh struct {
+	Interval       time.Duration `yaml:"interval"`        // REFRESH_INTERVAL（シーズン外）
+	SeasonInterval time.Duration `yaml:"season_interval"` // REFRESH_INTERVAL_SEASON（シーズン中）
+	SeasonMonths   string        `yaml:"season_months"`   // REFRESH_SEASON_MONTHS（例: 3-5。更新間隔を短くする月）
+}
+
+// Forecast は予報のシーズンの設定
+type Forecast struct {
+	SeasonMonths string `yaml:"season_months"` // FORECAST_SEASON_MONTHS（例: 2-5。指数・通知・カレンダーのシーズン）
+}
+
+// Night は夜間のまとめに使う時間帯の設定
+type Night struct {
+	Window string `yaml:"window"` // NIGHT_WINDOW（例: 20-5）
+}
+
+// Storage は投稿画像の保存先（Supabase Storage）の設定
+// 設定漏れに起動時に気付けるよう必須とし、画像付きの投稿を受け付けない場合はDisabledで明示する
+type Storage struct {
+	SupabaseURL        string `yaml:"supabase_url"`         // SUPABASE_URL（例: https://xxxx.supabase.co）
+	SupabaseServiceKey string `yaml:"supabase_service_key"` // SUPABASE_SERVICE_KEY
+	Disabled           bool   `yaml:"disabled"`             // STORAGE_DISABLED（trueの場合は画像付きの投稿を受け付けない）
+}
+
+// Push はWeb Push通知のVAPID鍵の設定（鍵が未設定の場合はプッシュ通知が無効）
+type Push struct {
+	VAPIDPublicKey  string `yaml:"vapid_public_key"`  // VAPID_PUBLIC_KEY
+	VAPIDPrivateKey string `yaml:"vapid_private_key"` // VAPID_PRIVATE_KEY
+	VAPIDSubject    string `yaml:"vapid_subject"`     // VAPID_SUBJECT（例: mailto:admin@example.com）
+}
+
+// Default は既定の設定を返す
+func Default() *Config {
+	refresh := scheduler.DefaultConfig()
+	nightConfig := night.DefaultConfig()
+	cacheOptions := cache.DefaultOptions()
+	return &Config{
+		Server: Server{
+			Port:            "8080",
+			ShutdownTimeout: 8 * time.Second,
+			AllowedOrigins:  []string{"http://localhost:3000", "http://localhost:3001", "https://bakuwaki-yoho.com"},
+			SiteURL:         "https://bakuwaki-yoho.com",
+		},
+		Log: Log{
+			Level: "info",
+		},
+		Data: Data{
+			Source:     "live",
+			FixtureDir: "./testdata/fixtures",
+		},
+		Cache: Cache{
+			Snapshot:        "db",
+			SnapshotPath:    "./cache_snapshot.json",
+			RequestInterval: cacheOptions.RequestInterval,
+			DiffHistory:     cacheOptions.DiffHistory,
+		},
+		Refresh: Refresh{
+			Interval:       refresh.Interval,
+			SeasonInterval: refresh.SeasonInterval,
+			SeasonMonths:   refresh.Season.String(),
+		},
+		Night: Night{
+			Window: fmt.Sprintf("%d-%d", nightConfig.StartHour, nightConfig.EndHour),
+		},
+		Forecast: Forecast{
+			SeasonMonths: level.DefaultSeason().String(),
+		},
+	}
+}
+
+// Load は設定ファイル（pathが空の場合は読み込まない）と環境変数から設定を読み込み、検証する
+func Load(path string) (*Config, error) {
+	return load(path, os.Getenv)
+}
+
+func load(path string, getenv func(string) string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	// 環境変数の形式の誤りと設定全体の検証結果をまとめて返す
+	problems := cfg.applyEnv(getenv)
+	var invalid *ValidationError
+	if err := cfg.Validate(); errors.As(err, &invalid) {
+		problems = append(problems, invalid.Problems...)
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// readFile はYAMLの設定ファイルを読み込む（書かれていない項目は現在の値のまま）
+// 項目名の書き間違いに気付けるよう、知らない項目があればエラーにする
+func (c *Config) readFile(path string) error {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(body))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("設定ファイルの形式が不正です（%s）: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv は設定されている環境変数の値で上書きし、読み込めなかった値の問題を返す
+func (c *Config) applyEnv(getenv func(string) string) []string {
+	var problems []string
+	str := func(name string, dst *string) {
+		if v := getenv(name); v != "" {
+			*dst = v
+		}
+	}
+	duration := func(name string, dst *time.Duration) {
+		if v := getenv(name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%sの値が不正です（例: 8s, 6h）: %q", name, v))
+				return
+			}
+			*dst = d
+		}
+	}
+	integer := func(name string, dst *int) {
+		if v := getenv(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%sの値が整数ではありません: %q", name, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	boolean := func(name string, dst *bool) {
+		if v := getenv(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%sの値が不正です（true または false）: %q", name, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	list := func(name string, dst *[]string) {
+		if v := getenv(name); v != "" {
+			var items []string
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			*dst = items
+		}
+	}
+
+	str("PORT", &c.Server.Port)
+	duration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	list("ALLOWED_ORIGINS", &c.Server.AllowedOrigins)
+	str("SITE_URL", &c.Server.SiteURL)
+	str("METRICS_TOKEN", &c.Server.MetricsToken)
+
+	str("LOG_LEVEL", &c.Log.Level)
+
+	str("DATABASE_URL", &c.Database.URL)
+
+	str("JWT_SECRET_KEY", &c.Auth.JWTSecret)
+	str("ADMIN_PASSWORD", &c.Auth.AdminPassword)
+	str("CRON_SECRET_KEY", &c.Auth.CronSecret)
+	str("DISPLAY_ID_SALT", &c.Auth.DisplayIDSalt)
+
+	str("DATA_SOURCE", &c.Data.Source)
+	str("PREDICTION_API_URL", &c.Data.PredictionAPIURL)
+	str("FIXTURE_DIR", &c.Data.FixtureDir)
+	str("TIDE_STATIONS_PATH", &c.Data.TideStationsPath)
+	str("INDEX_CONFIG_PATH", &c.Data.IndexConfigPath)
+
+	str("CACHE_SNAPSHOT", &c.Cache.Snapshot)
+	str("CACHE_SNAPSHOT_PATH", &c.Cache.SnapshotPath)
+	duration("CACHE_REQUEST_INTERVAL", &c.Cache.RequestInterval)
+	integer("CACHE_DIFF_HISTORY", &c.Cache.DiffHistory)
+
+	duration("REFRESH_INTERVAL", &c.Refresh.Interval)
+	duration("REFRESH_INTERVAL_SEASON", &c.Refresh.SeasonInterval)
+	str("REFRESH_SEASON_MONTHS", &c.Refresh.SeasonMonths)
+
+	str("NIGHT_WINDOW", &c.Night.Window)
+
+	str("FORECAST_SEASON_MONTHS", &c.Forecast.SeasonMonths)
+
+	str("SUPABASE_URL", &c.Storage.SupabaseURL)
+	str("SUPABASE_SERVICE_KEY", &c.Storage.SupabaseServiceKey)
+	boolean("STORAGE_DISABLED", &c.Storage.Disabled)
+
+	str("VAPID_PUBLIC_KEY", &c.Push.VAPIDPublicKey)
+	str("VAPID_PRIVATE_KEY", &c.Push.VAPIDPrivateKey)
+	str("VAPID_SUBJECT", &c.Push.VAPIDSubject)
+	return problems
+}
+
+// ValidationError は設定の検証で見つかった問題の一覧
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "設定が不正です: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate は設定全体を検証し、見つかった問題をまとめて返す
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		add("PORT（server.port）の値が不正です: %q", c.Server.Port)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		add("SHUTDOWN_TIMEOUT（server.shutdown_timeout）は0より大きくしてください（例: 8s）")
+	}
+	if len(c.Server.AllowedOrigins) == 0 {
+		add("ALLOWED_ORIGINS（server.allowed_origins）が空です")
+	}
+	for _, origin := range c.Server.AllowedOrigins {
+		// rs/corsのワイルドカード（* や https://*.example.com）はそのまま使う
+		if !strings.Contains(origin, "*") && !isHTTPURL(origin, false) {
+			add("ALLOWED_ORIGINS（server.allowed_origins）の値が不正です（例: https://example.com）: %q", origin)
+		}
+	}
+	if !isHTTPURL(c.Server.SiteURL, true) {
+		add("SITE_URL（server.site_url）の値が不正です（例: https://bakuwaki-yoho.com）: %q", c.Server.SiteURL)
+	}
+
+	if _, ok := parseLogLevel(c.Log.Level); !ok {
+		add("LOG_LEVEL（log.level）の値が不正です（debug, info, warn, error のいずれか）: %q", c.Log.Level)
+	}
+
+	if c.Database.URL == "" {
+		add("DATABASE_URL（database.url）が設定されていません")
+	}
+	if c.Auth.JWTSecret == "" {
+		add("JWT_SECRET_KEY（auth.jwt_secret）が設定されていません")
+	}
+
+	switch c.Data.Source {
+	case "live":
+		if c.Data.PredictionAPIURL == "" {
+			add("DATA_SOURCEがliveの場合はPREDICTION_API_URL（data.prediction_api_url）が必要です")
+		} else if !isHTTPURL(c.Data.PredictionAPIURL, true) {
+			add("PREDICTION_API_URL（data.prediction_api_url）の値が不正です: %q", c.Data.PredictionAPIURL)
+		}
+	case "fixture":
+		if c.Data.FixtureDir == "" {
+			add("DATA_SOURCEがfixtureの場合はFIXTURE_DIR（data.fixture_dir）が必要です")
+		}
+	default:
+		add("DATA_SOURCE（data.source）の値が不正です（live または fixture）: %q", c.Data.Source)
+	}
+
+	switch c.Cache.Snapshot {
+	case "db", "off":
+	case "file":
+		if c.Cache.SnapshotPath == "" {
+			add("CACHE_SNAPSHOTがfileの場合はCACHE_SNAPSHOT_PATH（cache.snapshot_path）が必要です")
+		}
+	default:
+		add("CACHE_SNAPSHOT（cache.snapshot）の値が不正です（db, file, off のいずれか）: %q", c.Cache.Snapshot)
+	}
+	if c.Cache.RequestInterval < 0 {
+		add("CACHE_REQUEST_INTERVAL（cache.request_interval）は0以上にしてください")
+	}
+	if c.Cache.DiffHistory < 1 {
+		add("CACHE_DIFF_HISTORY（cache.diff_history）は1以上にしてください")
+	}
+
+	if c.Refresh.Interval <= 0 {
+		add("REFRESH_INTERVAL（refresh.interval）は0より大きくしてください（例: 6h）")
+	}
+	if c.Refresh.SeasonInterval <= 0 {
+		add("REFRESH_INTERVAL_SEASON（refresh.season_interval）は0より大きくしてください（例: 1h）")
+	}
+	if _, err := level.ParseSeason(c.Refresh.SeasonMonths); err != nil {
+		add("REFRESH_SEASON_MONTHS（refresh.season_months）: %v", err)
+	}
+	if _, err := night.ParseWindow(c.Night.Window); err != nil {
+		add("NIGHT_WINDOW（night.window）: %v", err)
+	}
+	if _, err := level.ParseSeason(c.Forecast.SeasonMonths); err != nil {
+		add("FORECAST_SEASON_MONTHS（forecast.season_months）: %v", err)
+	}
+
+	if problem := c.Storage.validate(); problem != "" {
+		add("%s", problem)
+	}
+	if _, err := c.VAPIDKeys(); err != nil {
+		add("%v", err)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validate はSupabaseのURLが「https://ホスト」の形になっているか、サービスキーと組になっているかを確認する
+// 無効にする場合（STORAGE_DISABLED）はSupabaseの設定があると紛らわしいので、両方とも未設定であることを確認する
+func (s Storage) validate() string {
+	switch {
+	case s.Disabled:
+		if s.SupabaseURL != "" || s.SupabaseServiceKey != "" {
+			return "STORAGE_DISABLED（storage.disabled）とSUPABASE_URL/SUPABASE_SERVICE_KEYは同時に設定できません"
+		}
+		return ""
+	case s.SupabaseURL == "" && s.SupabaseServiceKey == "":
+		return "SUPABASE_URL（storage.supabase_url）とSUPABASE_SERVICE_KEY（storage.supabase_service_key）が設定されていません（画像付きの投稿を受け付けない場合はSTORAGE_DISABLED=true）"
+	case s.SupabaseURL == "":
+		return "SUPABASE_SERVICE_KEYを使うにはSUPABASE_URL（storage.supabase_url）も設定してください"
+	case s.SupabaseServiceKey == "":
+		return "SUPABASE_URLを使うにはSUPABASE_SERVICE_KEY（storage.supabase_service_key）も設定してください"
+	}
+	u, err := url.Parse(s.SupabaseURL)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("SUPABASE_URL（storage.supabase_url）の値が不正です（例: https://xxxx.supabase.co）: %q", s.SupabaseURL)
+	}
+	// ローカルのSupabase（supabase start）だけはhttpを許可する
+	if u.Scheme != "https" && !(u.Scheme == "http" && isLoopback(u.Hostname())) {
+		return fmt.Sprintf("SUPABASE_URL（storage.supabase_url）はhttps://で指定してください: %q", s.SupabaseURL)
+	}
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return fmt.Sprintf("SUPABASE_URL（storage.supabase_url）にはプロジェクトのURLだけを指定してください（/storage/v1 などは不要）: %q", s.SupabaseURL)
+	}
+	return ""
+}
+
+// StorageEnabled は投稿画像の保存先が有効かを返す（STORAGE_DISABLEDで無効にした場合はfalse）
+func (c *Config) StorageEnabled() bool {
+	return !c.Storage.Disabled && c.Storage.SupabaseURL != ""
+}
+
+// LogLevel はログの出力レベルを返す
+func (c *Config) LogLevel() slog.Level {
+	level, _ := parseLogLevel(c.Log.Level)
+	return level
+}
+
+func parseLogLevel(v string) (slog.Level, bool) {
+	switch strings.ToLower(v) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return slog.LevelInfo, false
+}
+
+// SchedulerConfig はキャッシュの定期更新の設定を返す（Validateで検証済みであること）
+func (c *Config) SchedulerConfig() scheduler.Config {
+	refreshSeason, err := level.ParseSeason(c.Refresh.SeasonMonths)
+	if err != nil {
+		refreshSeason = scheduler.DefaultConfig().Season
+	}
+	return scheduler.Config{
+		Interval:       c.Refresh.Interval,
+		SeasonInterval: c.Refresh.SeasonInterval,
+		Season:         refreshSeason,
+	}
+}
+
+// Season は予報のシーズンを返す（Validateで検証済みであること）
+func (c *Config) Season() level.Season {
+	season, err := level.ParseSeason(c.Forecast.SeasonMonths)
+	if err != nil {
+		return level.DefaultSeason()
+	}
+	return season
+}
+
+// NightConfig は夜の時間帯を返す（Validateで検証済みであること）
+func (c *Config) NightConfig() night.Config {
+	cfg, err := night.ParseWindow(c.Night.Window)
+	if err != nil {
+		return night.DefaultConfig()
+	}
+	return cfg
+}
+
+// CacheOptions はCacheManagerの設定を返す
+func (c *Config) CacheOptions() cache.Options {
+	return cache.Options{RequestInterval: c.Cache.RequestInterval, DiffHistory: c.Cache.DiffHistory}
+}
+
+// VAPIDKeys はVAPID鍵を読み込む。鍵が設定されていない場合はnilを返す
+func (c *Config) VAPIDKeys() (*push.Keys, error) {
+	if c.Push.VAPIDPublicKey == "" && c.Push.VAPIDPrivateKey == "" {
+		return nil, nil
+	}
+	if c.Push.VAPIDSubject == "" {
+		return nil, fmt.Errorf("VAPID鍵を使うにはVAPID_SUBJECT（push.vapid_subject）も設定してください")
+	}
+	return push.LoadKeys(c.Push.VAPIDPublicKey, c.Push.VAPIDPrivateKey, c.Push.VAPIDSubject)
+}
+
+// isHTTPURL はhttp(s)のURLでホストがあるかを返す。allowPathがfalseの場合はパスを許可しない（CORSのオリジン用）
+func isHTTPURL(v string, allowPath bool) bool {
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	return allowPath || u.Path == ""
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
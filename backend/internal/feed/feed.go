@@ -0,0 +1,189 @@
+// backend/internal/feed/feed.go
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Feed はAtom・RSSのどちらにも書き出せるフィード
+type Feed struct {
+	ID       string // 例: tag:bakuwaki-yoho.com,2025:forecast
+	Title    string
+	Subtitle string
+	Link     string // フィードの内容を表示するページ
+	SelfLink string // フィード自身のURL
+	Updated  time.Time
+	Entries  []Entry
+}
+
+// Entry はフィードの1記事
+type Entry struct {
+	ID        string
+	Title     string
+	Link      string
+	Author    string
+	Category  string
+	HTML      string // 本文（HTML）
+	Published time.Time
+	Updated   time.Time
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   atomAuthor  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Links     []atomLink    `xml:"link"`
+	Author    *atomAuthor   `xml:"author,omitempty"`
+	Category  *atomCategory `xml:"category,omitempty"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Content   atomContent   `xml:"content"`
+}
+
+// WriteAtom はAtom 1.0（RFC 4287）形式で書き出す
+func (f *Feed) WriteAtom(w io.Writer) error {
+	out := atomFeed{
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Subtitle,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+			{Href: f.SelfLink, Rel: "self", Type: "application/atom+xml"},
+		},
+		// フィードに著者がいない記事はこの著者になる
+		Author:  atomAuthor{Name: f.Title},
+		Entries: []atomEntry{},
+	}
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			ID:        e.ID,
+			Title:     e.Title,
+			Links:     []atomLink{{Href: e.Link, Rel: "alternate", Type: "text/html"}},
+			Published: e.Published.UTC().Format(time.RFC3339),
+			Updated:   e.Updated.UTC().Format(time.RFC3339),
+			Content:   atomContent{Type: "html", Body: e.HTML},
+		}
+		if e.Author != "" {
+			entry.Author = &atomAuthor{Name: e.Author}
+		}
+		if e.Category != "" {
+			entry.Category = &atomCategory{Term: e.Category}
+		}
+		out.Entries = append(out.Entries, entry)
+	}
+	return write(w, out)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssAtomLink struct {
+	XMLName xml.Name `xml:"atom:link"`
+	Href    string   `xml:"href,attr"`
+	Rel     string   `xml:"rel,attr"`
+	Type    string   `xml:"type,attr"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	Language      string      `xml:"language"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	SelfLink      rssAtomLink `xml:"atom:link"`
+	Items         []rssItem   `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Category    string  `xml:"category,omitempty"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+// WriteRSS はRSS 2.0形式で書き出す
+func (f *Feed) WriteRSS(w io.Writer) error {
+	description := f.Subtitle
+	if description == "" {
+		description = f.Title
+	}
+	out := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   description,
+			Language:      "ja",
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+			SelfLink:      rssAtomLink{Href: f.SelfLink, Rel: "self", Type: "application/rss+xml"},
+			Items:         []rssItem{},
+		},
+	}
+	for _, e := range f.Entries {
+		out.Channel.Items = append(out.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{IsPermaLink: "false", Value: e.ID},
+			Category:    e.Category,
+			Description: e.HTML,
+			PubDate:     e.Updated.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return write(w, out)
+}
+
+func write(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
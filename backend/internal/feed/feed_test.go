@@ -0,0 +1,85 @@
+// backend/internal/feed/feed_test.go
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	updated := time.Date(2026, 4, 12, 6, 0, 0, 0, time.UTC)
+	return &Feed{
+		ID:       "tag:example.com,2025:forecast",
+		Title:    "予報の更新",
+		Link:     "https://example.com/",
+		SelfLink: "https://api.example.com/api/feeds/forecast.atom",
+		Updated:  updated,
+		Entries: []Entry{{
+			ID:        "tag:example.com,2025:forecast-1",
+			Title:     "4/12(日) 湧き→大湧き",
+			Link:      "https://example.com/detail/2026-04-12",
+			Category:  "予報",
+			HTML:      "<p>予報が更新されました &amp; <b>大湧き</b></p>",
+			Published: updated,
+			Updated:   updated,
+		}},
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testFeed().WriteAtom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Content struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Atomとして読めません: %v\n%s", err, buf.String())
+	}
+	if parsed.Updated != "2026-04-12T06:00:00Z" || len(parsed.Entries) != 1 {
+		t.Fatalf("parsed = %+v", parsed)
+	}
+	if e := parsed.Entries[0]; e.Content.Type != "html" || !strings.Contains(e.Content.Body, "<b>大湧き</b>") {
+		t.Errorf("content = %+v", e.Content)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testFeed().WriteRSS(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Items []struct {
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("RSSとして読めません: %v\n%s", err, buf.String())
+	}
+	if parsed.Version != "2.0" || len(parsed.Channel.Items) != 1 {
+		t.Fatalf("parsed = %+v", parsed)
+	}
+	if item := parsed.Channel.Items[0]; item.GUID != "tag:example.com,2025:forecast-1" || item.PubDate != "Sun, 12 Apr 2026 06:00:00 +0000" {
+		t.Errorf("item = %+v", item)
+	}
+	if !strings.Contains(buf.String(), `<atom:link href="https://api.example.com/api/feeds/forecast.atom" rel="self"`) {
+		t.Errorf("atom:linkがありません\n%s", buf.String())
+	}
+}
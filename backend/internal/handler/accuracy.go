@@ -0,0 +1,76 @@
+// backend/internal/handler/accuracy.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 手動で再集計できる日数の上限
+const maxScoreDays = 120
+
+// 予報精度を取得する (GET /api/accuracy?from=YYYY-MM-DD&to=YYYY-MM-DD)
+// 期間を省略した場合は今シーズン（シーズンの初日〜今日）を対象とする
+func (h *Handler) getAccuracyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now().In(jst)
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	if from == "" {
+		// シーズン外の場合は直前のシーズンの初日から
+		start, _ := h.season.Range(now)
+		if start.After(now) {
+			start, _ = h.season.Range(start.AddDate(-1, 0, 0))
+		}
+		from = start.Format("2006-01-02")
+	}
+	if to == "" {
+		to = now.Format("2006-01-02")
+	}
+	fromDate, errFrom := time.Parse("2006-01-02", from)
+	toDate, errTo := time.Parse("2006-01-02", to)
+	if errFrom != nil || errTo != nil {
+		http.Error(w, "日付の形式が不正です（YYYY-MM-DD）", http.StatusBadRequest)
+		return
+	}
+	if toDate.Before(fromDate) {
+		http.Error(w, "期間の指定が不正です", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.accuracy.GetAccuracy(from, to)
+	if err != nil {
+		h.log(r).Error("予報精度の取得エラー", "error", err)
+		http.Error(w, "予報精度の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// 直近の夜の精度集計を実行する (POST /api/tasks/score-accuracy?days=N)
+func (h *Handler) scoreAccuracyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.checkCronSecret(w, r) {
+		return
+	}
+	days := 3
+	if d, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && d > 0 {
+		days = d
+	}
+	if days > maxScoreDays {
+		days = maxScoreDays
+	}
+	h.accuracy.RescoreInBackground(days)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Accuracy scoring triggered."))
+}
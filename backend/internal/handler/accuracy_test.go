@@ -0,0 +1,22 @@
+// backend/internal/handler/accuracy_test.go
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/config"
+)
+
+// TestScoreAccuracyHandlerRejectsGet は精度集計の実行がPOST以外を受け付けないことをテストする
+func TestScoreAccuracyHandlerRejectsGet(t *testing.T) {
+	h := &Handler{auth: config.Auth{CronSecret: "secret"}}
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/score-accuracy", nil)
+	req.Header.Set("X-Cron-Secret", "secret")
+	rec := httptest.NewRecorder()
+	h.scoreAccuracyHandler(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -4,7 +4,6 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -21,7 +20,7 @@ func (h *Handler) adminLoginHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "リクエストボディが不正です", http.StatusBadRequest)
 		return
 	}
-	adminPassword := os.Getenv("ADMIN_PASSWORD")
+	adminPassword := h.auth.AdminPassword
 	if adminPassword == "" {
 		http.Error(w, "管理人機能が設定されていません", http.StatusInternalServerError)
 		return
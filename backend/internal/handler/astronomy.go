@@ -0,0 +1,108 @@
+// backend/internal/handler/astronomy.go
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/astronomy"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// 月齢カレンダーの計算結果は日付と地点だけで決まるため、ブラウザ・CDNには1日キャッシュさせる
+const moonCalendarMaxAge = 24 * time.Hour
+
+// 期間を指定しないリクエスト（今シーズン）の応答を地点ごとに保持する
+// シーズンが変わったら同じ地点のエントリを上書きするので、地点の数より増えない
+var (
+	moonCalendarMu   sync.Mutex
+	moonCalendarMemo = make(map[string]moonCalendarEntry)
+)
+
+type moonCalendarEntry struct {
+	from time.Time
+	body []byte
+}
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// 月齢カレンダーを取得する (GET /api/moon-calendar?from=YYYY-MM-DD&to=YYYY-MM-DD&spot=...)
+// 期間が未指定の場合は今シーズン（シーズン外なら次のシーズン）を返す
+// 一度に取得できるのはシーズンの日数（約120日）まで
+func (h *Handler) getMoonCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	spotID := r.URL.Query().Get("spot")
+	if spotID == "" {
+		spotID = spot.DefaultID
+	}
+	s, ok := spot.Get(spotID)
+	if !ok {
+		http.Error(w, "指定された地点は存在しません", http.StatusBadRequest)
+		return
+	}
+
+	from, to := h.season.Range(time.Now().In(jst))
+	maxDays := int(to.Sub(from).Hours()/24) + 1
+	seasonFrom, seasonTo := from, to
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		t, err := time.ParseInLocation("2006-01-02", fromStr, jst)
+		if err != nil {
+			http.Error(w, "fromの形式が不正です（YYYY-MM-DD）", http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		t, err := time.ParseInLocation("2006-01-02", toStr, jst)
+		if err != nil {
+			http.Error(w, "toの形式が不正です（YYYY-MM-DD）", http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+	if to.Before(from) || int(to.Sub(from).Hours()/24) >= maxDays {
+		http.Error(w, fmt.Sprintf("期間の指定が不正です（最大%d日）", maxDays), http.StatusBadRequest)
+		return
+	}
+
+	isSeason := from.Equal(seasonFrom) && to.Equal(seasonTo)
+	var body []byte
+	if isSeason {
+		moonCalendarMu.Lock()
+		if entry, ok := moonCalendarMemo[spotID]; ok && entry.from.Equal(from) {
+			body = entry.body
+		}
+		moonCalendarMu.Unlock()
+	}
+	if body == nil {
+		days := []model.AstronomyDay{}
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			days = append(days, astronomy.Day(s.Latitude, s.Longitude, d))
+		}
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(model.MoonCalendarResponse{
+			SpotID: spotID,
+			From:   from.Format("2006-01-02"),
+			To:     to.Format("2006-01-02"),
+			Days:   days,
+		})
+		body = buf.Bytes()
+		if isSeason {
+			moonCalendarMu.Lock()
+			moonCalendarMemo[spotID] = moonCalendarEntry{from: from, body: body}
+			moonCalendarMu.Unlock()
+		}
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(moonCalendarMaxAge.Seconds())))
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
@@ -0,0 +1,43 @@
+// backend/internal/handler/astronomy_test.go
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+)
+
+// TestMoonCalendarHandlerLimitsRange は月齢カレンダーの期間がシーズンの日数までに制限されることをテストする
+func TestMoonCalendarHandlerLimitsRange(t *testing.T) {
+	h := &Handler{season: level.DefaultSeason()}
+	cases := []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusOK},
+		{"?from=2026-02-01&to=2026-05-31", http.StatusOK},
+		{"?from=2026-02-01&to=2026-06-01", http.StatusBadRequest},
+		{"?from=2026-01-01&to=2027-01-31", http.StatusBadRequest},
+		{"?from=2026-03-02&to=2026-03-01", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		h.getMoonCalendarHandler(rec, httptest.NewRequest(http.MethodGet, "/api/moon-calendar"+c.query, nil))
+		if rec.Code != c.want {
+			t.Errorf("%q: status = %d, want %d", c.query, rec.Code, c.want)
+		}
+		if c.want == http.StatusOK && rec.Header().Get("Cache-Control") == "" {
+			t.Errorf("%q: Cache-Control が設定されていません", c.query)
+		}
+	}
+
+	// 今シーズンの応答は2回目以降も同じ内容を返す
+	first, second := httptest.NewRecorder(), httptest.NewRecorder()
+	h.getMoonCalendarHandler(first, httptest.NewRequest(http.MethodGet, "/api/moon-calendar", nil))
+	h.getMoonCalendarHandler(second, httptest.NewRequest(http.MethodGet, "/api/moon-calendar", nil))
+	if first.Body.String() != second.Body.String() {
+		t.Error("今シーズンの応答が呼び出しごとに異なります")
+	}
+}
@@ -6,13 +6,16 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/metrics"
 	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
 )
 
 var (
 	banMu         sync.RWMutex
 	bannedDevices = make(map[string]bool)
+	banLoadedAt   time.Time // 最後にBANリストを読み込んだ時刻（未読み込みはゼロ値）
 )
 
 // DBからBANリストをメモリに読み込む
@@ -34,10 +37,18 @@ func loadBannedDevices(db *sql.DB) error {
 
 	banMu.Lock()
 	bannedDevices = newCache
+	banLoadedAt = time.Now()
 	banMu.Unlock()
 	return rows.Err()
 }
 
+// BANリストを最後に読み込んだ時刻を返す
+func bannedDevicesLoadedAt() (time.Time, bool) {
+	banMu.RLock()
+	defer banMu.RUnlock()
+	return banLoadedAt, !banLoadedAt.IsZero()
+}
+
 // デバイスIDがBANされているか確認する
 func isDeviceBanned(deviceID string) bool {
 	banMu.RLock()
@@ -53,6 +64,7 @@ func checkBanStatus(w http.ResponseWriter, r *http.Request) bool {
 		return true // デバイスIDなしは通す（レート制限でIP制限がかかる）
 	}
 	if isDeviceBanned(deviceID) {
+		metrics.BanHits.Inc()
 		http.Error(w, "投稿できません", http.StatusForbidden)
 		return false
 	}
@@ -68,7 +80,7 @@ func (h *Handler) listBannedDevicesHandler(w http.ResponseWriter, r *http.Reques
 
 	rows, err := h.db.Query("SELECT id, device_id, reason, banned_at FROM banned_devices ORDER BY banned_at DESC")
 	if err != nil {
-		h.logger.Error("BANリスト取得エラー", "error", err)
+		h.log(r).Error("BANリスト取得エラー", "error", err)
 		http.Error(w, "BANリストの取得に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -78,7 +90,7 @@ func (h *Handler) listBannedDevicesHandler(w http.ResponseWriter, r *http.Reques
 	for rows.Next() {
 		var b model.BannedDevice
 		if err := rows.Scan(&b.ID, &b.DeviceID, &b.Reason, &b.BannedAt); err != nil {
-			h.logger.Error("BANリストスキャンエラー", "error", err)
+			h.log(r).Error("BANリストスキャンエラー", "error", err)
 			continue
 		}
 		bans = append(bans, b)
@@ -119,13 +131,13 @@ func (h *Handler) banDeviceHandler(w http.ResponseWriter, r *http.Request) {
 		req.DeviceID, req.Reason,
 	)
 	if err != nil {
-		h.logger.Error("デバイスBANエラー", "error", err)
+		h.log(r).Error("デバイスBANエラー", "error", err)
 		http.Error(w, "BANに失敗しました", http.StatusInternalServerError)
 		return
 	}
 
 	if err := loadBannedDevices(h.db); err != nil {
-		h.logger.Error("BANキャッシュ更新エラー", "error", err)
+		h.log(r).Error("BANキャッシュ更新エラー", "error", err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -149,7 +161,7 @@ func (h *Handler) unbanDeviceHandler(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.db.Exec("DELETE FROM banned_devices WHERE device_id = $1", deviceID)
 	if err != nil {
-		h.logger.Error("BAN解除エラー", "error", err)
+		h.log(r).Error("BAN解除エラー", "error", err)
 		http.Error(w, "BAN解除に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -161,7 +173,7 @@ func (h *Handler) unbanDeviceHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := loadBannedDevices(h.db); err != nil {
-		h.logger.Error("BANキャッシュ更新エラー", "error", err)
+		h.log(r).Error("BANキャッシュ更新エラー", "error", err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,134 @@
+// backend/internal/handler/calendar.go
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/astronomy"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/ical"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// カレンダーに載せる予測値の既定の下限（大湧き）
+const defaultCalendarLevel = 4
+
+// カレンダーアプリに再取得を促す間隔
+const calendarRefreshInterval = time.Hour
+
+// 見込みのある夜をiCalendar形式で取得する (GET /api/calendar.ics?threshold=1.15&spot=...)
+// 予定のUIDは日付と地点から決めるため、予報が変わるとカレンダーアプリ側の予定が更新される
+func (h *Handler) getCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	threshold := level.Threshold(defaultCalendarLevel)
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+			http.Error(w, "thresholdは0以上の数値で指定してください", http.StatusBadRequest)
+			return
+		}
+		threshold = t
+	}
+	spotID := r.URL.Query().Get("spot")
+	if spotID == "" {
+		spotID = spot.DefaultID
+	}
+	s, ok := spot.Get(spotID)
+	if !ok {
+		http.Error(w, "指定された地点は存在しません", http.StatusBadRequest)
+		return
+	}
+
+	entry, ok := h.cache.GetPredictionEntry()
+	if !ok {
+		http.Error(w, "予測データはまだ利用できません。", http.StatusServiceUnavailable)
+		return
+	}
+	var days []struct {
+		Date            string   `json:"date"`
+		PredictedAmount float64  `json:"predicted_amount"`
+		MoonAge         *float64 `json:"moon_age"`
+	}
+	if err := json.Unmarshal(entry.Data, &days); err != nil {
+		h.log(r).Error("カレンダー用の予測データの解析エラー", "error", err)
+		http.Error(w, "カレンダーの作成に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	host := "localhost"
+	if u, err := url.Parse(h.siteURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	cal := ical.Calendar{
+		ProductID: "-//bakuwaki-yoho//forecast//JA",
+		Name:      "ホタルイカ爆湧き予報（" + s.Name + "）",
+		Refresh:   calendarRefreshInterval,
+	}
+	for _, d := range days {
+		date, err := time.ParseInLocation("2006-01-02", d.Date, jst)
+		if err != nil || !h.season.Contains(date) || d.PredictedAmount < threshold {
+			continue
+		}
+		lvl := level.FromAmount(d.PredictedAmount)
+		start, end := h.night.Window(date)
+		lines := []string{fmt.Sprintf("予測値 %.2f（%s）", d.PredictedAmount, level.Name(lvl))}
+
+		moonAge := d.MoonAge
+		if detail, ok := h.cache.GetDetail(s.ID, d.Date); ok {
+			if timeline, err := night.BuildTimeline(h.night, h.scoring.Config().TimelineWeights(), h.scoring.Config().TimelineWind(), detail, s.ShoreBearing); err == nil {
+				lines = append(lines, timeline.Summary)
+				if timeline.BestWindow != nil {
+					start, end = timeline.BestWindow.Start, timeline.BestWindow.End
+				}
+			}
+			if summary, err := night.Summarize(h.night, detail); err == nil && summary.LowestTide != nil {
+				lines = append(lines, fmt.Sprintf("最も潮が引くのは%s（%.0fcm）", summary.LowestTide.Time.In(jst).Format("15:04"), summary.LowestTide.Height))
+			}
+			if detail.Tide.Moon.Age != nil {
+				moonAge = detail.Tide.Moon.Age
+			}
+		}
+		if moonAge == nil {
+			age := astronomy.MoonAge(date.Add(12 * time.Hour))
+			moonAge = &age
+		}
+		lines = append(lines, fmt.Sprintf("月齢 %.1f（%s）", *moonAge, astronomy.PhaseName(*moonAge)))
+
+		link := strings.TrimSuffix(h.siteURL, "/") + "/detail/" + d.Date
+		if s.ID != spot.DefaultID {
+			link += "?spot=" + s.ID
+		}
+		lines = append(lines, link)
+
+		cal.Events = append(cal.Events, ical.Event{
+			UID: d.Date + "-" + s.ID + "@" + host,
+			// 予報が更新されるたびに増える値にする
+			Sequence:    int(entry.FetchedAt.Unix() / 60),
+			Stamp:       entry.FetchedAt,
+			Start:       start,
+			End:         end,
+			Summary:     fmt.Sprintf("%s予報（%.2f）", level.Name(lvl), d.PredictedAmount),
+			Description: strings.Join(lines, "\n"),
+			Location:    s.Name,
+			URL:         link,
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="bakuwaki.ics"`)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(forecastMaxAge.Seconds())))
+	if err := cal.Write(w); err != nil {
+		h.log(r).Error("カレンダーの書き出しエラー", "error", err)
+	}
+}
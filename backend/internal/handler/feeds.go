@@ -0,0 +1,277 @@
+// backend/internal/handler/feeds.go
+package handler
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/feed"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/history"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// フィードに載せる記事の数と、予報の変化をさかのぼる期間
+const (
+	feedEntryLimit       = 30
+	forecastFeedLookback = 30 * 24 * time.Hour
+)
+
+// フィードを取得する (GET /api/feeds/{forecast|announcements}.{atom|rss})
+//
+//	forecast       7日間の予測が大きく変わったときの更新
+//	announcements  管理人ラベルの投稿と固定された投稿
+func (h *Handler) getFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	pathSegments := splitPath(r.URL.Path)
+	if len(pathSegments) != 3 {
+		http.NotFound(w, r)
+		return
+	}
+	file := pathSegments[2]
+	format := strings.TrimPrefix(path.Ext(file), ".")
+	if format != "atom" && format != "rss" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var f *feed.Feed
+	var err error
+	switch strings.TrimSuffix(file, path.Ext(file)) {
+	case "forecast":
+		f, err = h.forecastFeed()
+	case "announcements":
+		f, err = h.announcementsFeed()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.log(r).Error("フィードの作成エラー", "feed", file, "error", err)
+		http.Error(w, "フィードの作成に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	f.SelfLink = requestURL(r)
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(forecastMaxAge.Seconds())))
+	if format == "atom" {
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		err = f.WriteAtom(w)
+	} else {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		err = f.WriteRSS(w)
+	}
+	if err != nil {
+		h.log(r).Error("フィードの書き出しエラー", "feed", file, "error", err)
+	}
+}
+
+// forecastChange は予測データの更新のうち、大きな変化があったもの
+type forecastChange struct {
+	diff     *model.PredictionDiff
+	days     []model.PredictionDayDiff // 大きく変わった日
+	forecast []cache.ForecastDay       // 更新後の予測全体
+}
+
+// forecastChanges は古い順に並んだスナップショットを前後で比較し、大きな変化があった更新を古い順に返す
+// 解析できないスナップショットは飛ばし、その次のスナップショットは最後に解析できたものと比較する
+func forecastChanges(snapshots []history.Snapshot) []forecastChange {
+	changes := []forecastChange{}
+	var previous *cache.Entry
+	for _, snap := range snapshots {
+		forecast, err := cache.ForecastDays(snap.Payload)
+		if err != nil {
+			continue
+		}
+		current := &cache.Entry{Data: snap.Payload, FetchedAt: snap.FetchedAt, Hash: snap.Hash}
+		if previous != nil {
+			if diff, err := cache.DiffPredictions(previous, current); err == nil {
+				if days := cache.MaterialDays(diff); len(days) > 0 {
+					changes = append(changes, forecastChange{diff: diff, days: days, forecast: forecast})
+				}
+			}
+		}
+		previous = current
+	}
+	return changes
+}
+
+// forecastFeed は予測が大きく変わった更新を新しい順に並べたフィードを作る
+func (h *Handler) forecastFeed() (*feed.Feed, error) {
+	snapshots, err := h.history.RecentSnapshots(time.Now().Add(-forecastFeedLookback))
+	if err != nil {
+		return nil, err
+	}
+	changes := forecastChanges(snapshots)
+
+	site := strings.TrimSuffix(h.siteURL, "/")
+	f := &feed.Feed{
+		ID:       h.feedTag("forecast"),
+		Title:    "ホタルイカ爆湧き予報 予報の更新",
+		Subtitle: "7日間の予測が大きく変わったときにお知らせします",
+		Link:     site + "/",
+		Updated:  time.Now(),
+		Entries:  []feed.Entry{},
+	}
+	if len(snapshots) > 0 {
+		f.Updated = snapshots[len(snapshots)-1].FetchedAt
+	}
+	for i := len(changes) - 1; i >= 0 && len(f.Entries) < feedEntryLimit; i-- {
+		c := changes[i]
+		top := c.days[0]
+		for _, d := range c.days[1:] {
+			if *d.CurrentAmount > *top.CurrentAmount {
+				top = d
+			}
+		}
+		title := fmt.Sprintf("%s %s", level.FormatDate(top.Date), levelChange(top))
+		if len(c.days) > 1 {
+			title += fmt.Sprintf(" ほか%d日", len(c.days)-1)
+		}
+
+		var body strings.Builder
+		body.WriteString("<p>予報が更新されました。</p><ul>")
+		for _, d := range c.days {
+			fmt.Fprintf(&body, "<li>%s: %s</li>", html.EscapeString(level.FormatDate(d.Date)), html.EscapeString(amountChange(d)))
+		}
+		body.WriteString("</ul><p>7日間の予報</p><ul>")
+		for _, d := range c.forecast {
+			fmt.Fprintf(&body, `<li><a href="%s">%s</a>: %s（%.2f）</li>`,
+				html.EscapeString(site+"/detail/"+d.Date), html.EscapeString(level.FormatDate(d.Date)), html.EscapeString(level.Name(d.Level)), d.Amount)
+		}
+		body.WriteString("</ul>")
+
+		hash, fetchedAt := c.diff.ToHash, c.diff.ToFetchedAt
+		f.Entries = append(f.Entries, feed.Entry{
+			ID:        h.feedTag("forecast-" + hash[:min(16, len(hash))] + "-" + fmt.Sprint(fetchedAt.Unix())),
+			Title:     title,
+			Link:      site + "/detail/" + top.Date,
+			Category:  "予報",
+			HTML:      body.String(),
+			Published: fetchedAt,
+			Updated:   fetchedAt,
+		})
+	}
+	return f, nil
+}
+
+// announcementsFeed は管理人ラベルの投稿と固定された投稿を新しい順に並べたフィードを作る
+func (h *Handler) announcementsFeed() (*feed.Feed, error) {
+	rows, err := h.db.Query(`SELECT id, username, content, image_urls, label, is_pinned, created_at
+		FROM posts
+		WHERE label = '管理人' OR is_pinned
+		ORDER BY created_at DESC
+		LIMIT $1`, feedEntryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("お知らせ投稿クエリ失敗: %w", err)
+	}
+	defer rows.Close()
+
+	site := strings.TrimSuffix(h.siteURL, "/")
+	f := &feed.Feed{
+		ID:       h.feedTag("announcements"),
+		Title:    "ホタルイカ爆湧き予報 お知らせ",
+		Subtitle: "管理人からのお知らせと固定された投稿",
+		Link:     site + "/",
+		Updated:  time.Now(),
+		Entries:  []feed.Entry{},
+	}
+	for rows.Next() {
+		var id int
+		var username, content, label string
+		var imageURLs pq.StringArray
+		var isPinned bool
+		var createdAt time.Time
+		if err := rows.Scan(&id, &username, &content, &imageURLs, &label, &isPinned, &createdAt); err != nil {
+			return nil, fmt.Errorf("お知らせ投稿のスキャン失敗: %w", err)
+		}
+		category := label
+		if isPinned {
+			category = "固定"
+		}
+		var body strings.Builder
+		for _, line := range strings.Split(content, "\n") {
+			fmt.Fprintf(&body, "<p>%s</p>", html.EscapeString(line))
+		}
+		for _, u := range imageURLs {
+			fmt.Fprintf(&body, `<p><img src="%s" alt=""></p>`, html.EscapeString(u))
+		}
+		f.Entries = append(f.Entries, feed.Entry{
+			ID:        h.feedTag(fmt.Sprintf("post-%d", id)),
+			Title:     feedTitle(content),
+			Link:      site + "/",
+			Author:    username,
+			Category:  category,
+			HTML:      body.String(),
+			Published: createdAt,
+			Updated:   createdAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(f.Entries) > 0 {
+		f.Updated = f.Entries[0].Updated
+	}
+	return f, nil
+}
+
+// requestURL はリクエストされたURLを返す（プロキシ経由の場合はX-Forwarded-Protoのスキームを使う）
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
+
+// feedTag はフィード・記事のIDにするtag URI（RFC 4151）を返す
+func (h *Handler) feedTag(name string) string {
+	host := "localhost"
+	if u, err := url.Parse(h.siteURL); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return "tag:" + host + ",2025:" + name
+}
+
+// feedTitle は本文の1行目を記事のタイトルにする（長い場合は省略する）
+func feedTitle(content string) string {
+	first := strings.TrimSpace(strings.SplitN(content, "\n", 2)[0])
+	if r := []rune(first); len(r) > 40 {
+		return string(r[:40]) + "…"
+	}
+	if first == "" {
+		return "お知らせ"
+	}
+	return first
+}
+
+func levelChange(d model.PredictionDayDiff) string {
+	if d.PreviousAmount == nil {
+		return level.Name(*d.Level) + "予報"
+	}
+	if *d.Level == *d.PreviousLevel {
+		return level.Name(*d.Level) + "のまま予測値が変化"
+	}
+	return level.Name(*d.PreviousLevel) + "→" + level.Name(*d.Level)
+}
+
+func amountChange(d model.PredictionDayDiff) string {
+	if d.PreviousAmount == nil {
+		return fmt.Sprintf("%s（%.2f）", level.Name(*d.Level), *d.CurrentAmount)
+	}
+	return fmt.Sprintf("%s → %s（%.2f → %.2f）", level.Name(*d.PreviousLevel), level.Name(*d.Level), *d.PreviousAmount, *d.CurrentAmount)
+}
@@ -0,0 +1,44 @@
+// backend/internal/handler/feeds_test.go
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/history"
+)
+
+// TestForecastChanges はスナップショットの前後の差分から大きな変化があった更新だけを選ぶことをテストする
+func TestForecastChanges(t *testing.T) {
+	base := time.Date(2026, 4, 10, 6, 0, 0, 0, jst)
+	snapshots := []history.Snapshot{
+		{FetchedAt: base, Hash: "a", Payload: []byte(`[{"date":"2026-04-10","predicted_amount":0.5},{"date":"2026-04-11","predicted_amount":1.0}]`)},
+		// 小さな変化だけ（レベルも変わらない）
+		{FetchedAt: base.Add(time.Hour), Hash: "b", Payload: []byte(`[{"date":"2026-04-10","predicted_amount":0.55},{"date":"2026-04-11","predicted_amount":1.05}]`)},
+		// 4/11が湧き→大湧き、4/12が湧きで新しく追加
+		{FetchedAt: base.Add(2 * time.Hour), Hash: "c", Payload: []byte(`[{"date":"2026-04-10","predicted_amount":0.6},{"date":"2026-04-11","predicted_amount":1.2},{"date":"2026-04-12","predicted_amount":0.95}]`)},
+		// 不正なペイロードは飛ばし、次はcと比較する
+		{FetchedAt: base.Add(3 * time.Hour), Hash: "d", Payload: []byte(`{"error":"x"}`)},
+		// 小さな変化だけ
+		{FetchedAt: base.Add(4 * time.Hour), Hash: "e", Payload: []byte(`[{"date":"2026-04-10","predicted_amount":0.6},{"date":"2026-04-11","predicted_amount":1.2},{"date":"2026-04-12","predicted_amount":0.91}]`)},
+		// レベルは同じだが大きく下がった
+		{FetchedAt: base.Add(5 * time.Hour), Hash: "f", Payload: []byte(`[{"date":"2026-04-10","predicted_amount":0.4},{"date":"2026-04-11","predicted_amount":1.2},{"date":"2026-04-12","predicted_amount":0.91}]`)},
+	}
+	changes := forecastChanges(snapshots)
+	if len(changes) != 2 {
+		t.Fatalf("len(changes) = %d, want 2: %+v", len(changes), changes)
+	}
+	c := changes[0]
+	if c.diff.ToHash != "c" || len(c.days) != 2 || len(c.forecast) != 3 {
+		t.Fatalf("changes[0] = %+v", c)
+	}
+	if got := levelChange(c.days[0]); c.days[0].Date != "2026-04-11" || got != "湧き→大湧き" {
+		t.Errorf("changes[0].days[0] = %s %s", c.days[0].Date, got)
+	}
+	if got := levelChange(c.days[1]); c.days[1].Date != "2026-04-12" || got != "湧き予報" {
+		t.Errorf("changes[0].days[1] = %s %s", c.days[1].Date, got)
+	}
+	if c := changes[1]; c.diff.FromHash != "e" || c.diff.ToHash != "f" || len(c.days) != 1 || c.days[0].Date != "2026-04-10" {
+		t.Errorf("changes[1] = %+v", c)
+	}
+}
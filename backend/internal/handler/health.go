@@ -0,0 +1,175 @@
+// backend/internal/handler/health.go
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// 確認結果
+const (
+	checkOK    = "ok"
+	checkStale = "stale"
+	checkFail  = "fail"
+)
+
+// データベースへの疎通確認のタイムアウト
+const readinessPingTimeout = 2 * time.Second
+
+// 詳細データを揃えておく期間（今日から何日先まで）
+const readinessDetailDays = 7
+
+// プロセスの起動時刻
+var startedAt = time.Now()
+
+// プロセスが動いているかを返す (GET /healthz)
+// 依存先は確認しないので、Cloud Runの生存確認に使う
+func (h *Handler) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":         checkOK,
+		"uptime_seconds": int64(time.Since(startedAt).Seconds()),
+	})
+}
+
+// 予報を返せる状態かを依存先ごとに確認する (GET /readyz)
+// いずれかが使えない場合は503、古いデータで応答している場合は200でstatusをdegradedにする
+func (h *Handler) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now()
+	resp := model.ReadinessResponse{
+		Status:    checkOK,
+		CheckedAt: now,
+		Checks: map[string]model.HealthCheck{
+			"database":         h.checkDatabase(r),
+			"prediction_cache": h.checkPredictionCache(now),
+			"detail_cache":     h.checkDetailCache(now),
+			"ban_list":         h.checkBanList(r, now),
+		},
+	}
+	for _, c := range resp.Checks {
+		if c.Status == checkFail {
+			resp.Status = checkFail
+		} else if c.Status == checkStale && resp.Status == checkOK {
+			resp.Status = "degraded"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if resp.Status == checkFail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) checkDatabase(r *http.Request) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessPingTimeout)
+	defer cancel()
+	start := time.Now()
+	err := h.db.PingContext(ctx)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		h.log(r).Error("ヘルスチェックのデータベース接続エラー", "error", err)
+		return model.HealthCheck{Status: checkFail, Message: "データベースに接続できません", LatencyMs: &latency}
+	}
+	return model.HealthCheck{Status: checkOK, LatencyMs: &latency}
+}
+
+// checkPredictionCache は予測データがあるか、更新間隔の2倍より古くないかを確認する
+func (h *Handler) checkPredictionCache(now time.Time) model.HealthCheck {
+	entry, ok := h.cache.GetPredictionEntry()
+	if !ok {
+		return model.HealthCheck{Status: checkFail, Message: "予測データがまだありません"}
+	}
+	age := ageSeconds(now, entry.FetchedAt)
+	switch {
+	case entry.Stale:
+		return model.HealthCheck{Status: checkStale, Message: "前回起動時のスナップショットのデータです", AgeSeconds: age}
+	case now.Sub(entry.FetchedAt) > 2*h.scheduler.Interval():
+		return model.HealthCheck{Status: checkStale, Message: "予測データが更新されていません", AgeSeconds: age}
+	}
+	return model.HealthCheck{Status: checkOK, AgeSeconds: age}
+}
+
+// checkDetailCache は全地点について今日から6日後までの詳細データが揃っているかを確認する
+// 今日の分がない場合は失敗、先の日付だけがない場合（日付が変わってから次の更新までなど）は古いデータとして扱う
+// 型付きの形式（schema=2）に変換できなかった日付も古いデータとして扱い、Missingに「（schema=2）」を付けて示す
+// 年齢は揃っている中で最も古いもの
+func (h *Handler) checkDetailCache(now time.Time) model.HealthCheck {
+	check := model.HealthCheck{Status: checkOK}
+	var oldest time.Time
+	stale, dateMissing, todayMissing, typedMissing := false, false, false, false
+	for _, s := range spot.All() {
+		for i := 0; i < readinessDetailDays; i++ {
+			date := now.In(jst).AddDate(0, 0, i).Format("2006-01-02")
+			entry, ok := h.cache.GetDetailEntry(s.ID, date)
+			if !ok {
+				check.Missing = append(check.Missing, s.ID+":"+date)
+				dateMissing = true
+				todayMissing = todayMissing || i == 0
+				continue
+			}
+			if _, ok := h.cache.GetDetailTypedEntry(s.ID, date); !ok {
+				check.Missing = append(check.Missing, s.ID+":"+date+"（schema=2）")
+				typedMissing = true
+			}
+			if oldest.IsZero() || entry.FetchedAt.Before(oldest) {
+				oldest = entry.FetchedAt
+			}
+			stale = stale || entry.Stale
+		}
+	}
+	if !oldest.IsZero() {
+		check.AgeSeconds = ageSeconds(now, oldest)
+	}
+	switch {
+	case todayMissing:
+		check.Status = checkFail
+		check.Message = "今日の詳細データがありません"
+	case dateMissing:
+		check.Status = checkStale
+		check.Message = "先の日付の詳細データが揃っていません"
+	case typedMissing:
+		check.Status = checkStale
+		check.Message = "型付きの形式（schema=2）に変換できなかった詳細データがあります"
+	case stale || now.Sub(oldest) > 2*h.scheduler.Interval():
+		check.Status = checkStale
+		check.Message = "詳細データが更新されていません"
+	}
+	return check
+}
+
+// checkBanList はBANリストを読み込めているかを確認する（読み込めていない場合は再度読み込む）
+func (h *Handler) checkBanList(r *http.Request, now time.Time) model.HealthCheck {
+	loadedAt, ok := bannedDevicesLoadedAt()
+	if !ok {
+		if err := loadBannedDevices(h.db); err != nil {
+			h.log(r).Error("BANリストの再読み込みエラー", "error", err)
+		}
+		loadedAt, ok = bannedDevicesLoadedAt()
+	}
+	if !ok {
+		return model.HealthCheck{Status: checkFail, Message: "BANリストを読み込めていません"}
+	}
+	return model.HealthCheck{Status: checkOK, AgeSeconds: ageSeconds(now, loadedAt)}
+}
+
+func ageSeconds(now, t time.Time) *int64 {
+	age := int64(now.Sub(t).Seconds())
+	return &age
+}
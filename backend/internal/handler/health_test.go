@@ -0,0 +1,66 @@
+// backend/internal/handler/health_test.go
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scheduler"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// TestCheckDetailCache は先の日付だけがない場合はdegraded（stale）、今日の分がない場合は失敗になることをテストする
+func TestCheckDetailCache(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cm := cache.NewCacheManagerWithOptions(logger, provider.NewFixtureSet("../../testdata/fixtures"), cache.Options{})
+	cm.FetchAndCacheDetailData()
+	h := &Handler{logger: logger, cache: cm, scheduler: scheduler.New(logger, cm, scheduler.DefaultConfig())}
+
+	now := time.Now()
+	if check := h.checkDetailCache(now); check.Status != checkOK {
+		t.Errorf("揃っている場合のstatus = %q, want ok: %+v", check.Status, check)
+	}
+	// 日付が変わった直後は6日後の分がまだない
+	if check := h.checkDetailCache(now.AddDate(0, 0, 1)); check.Status != checkStale || len(check.Missing) == 0 {
+		t.Errorf("先の日付がない場合のstatus = %q, want stale: %+v", check.Status, check)
+	}
+	if check := h.checkDetailCache(now.AddDate(0, 0, 7)); check.Status != checkFail {
+		t.Errorf("今日の分がない場合のstatus = %q, want fail: %+v", check.Status, check)
+	}
+}
+
+// malformedWeather は気温の要素数が時刻と合わない気象データを返す
+type malformedWeather struct {
+	provider.WeatherProvider
+}
+
+func (p malformedWeather) FetchWeather(s spot.Spot, date time.Time) (map[string]interface{}, error) {
+	data, err := p.WeatherProvider.FetchWeather(s, date)
+	if err != nil {
+		return nil, err
+	}
+	hourly := data["hourly"].(map[string]interface{})
+	temps := hourly["temperature_2m"].([]interface{})
+	hourly["temperature_2m"] = temps[:len(temps)-1]
+	return data, nil
+}
+
+// TestCheckDetailCacheReportsTypedFailure は型付きの形式に変換できなかった詳細データをdegraded（stale）として示すことをテストする
+func TestCheckDetailCacheReportsTypedFailure(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	providers := provider.NewFixtureSet("../../testdata/fixtures")
+	providers.Weather = malformedWeather{providers.Weather}
+	cm := cache.NewCacheManagerWithOptions(logger, providers, cache.Options{})
+	cm.FetchAndCacheDetailData()
+	h := &Handler{logger: logger, cache: cm, scheduler: scheduler.New(logger, cm, scheduler.DefaultConfig())}
+
+	check := h.checkDetailCache(time.Now())
+	if check.Status != checkStale || len(check.Missing) == 0 || !strings.Contains(check.Message, "schema=2") {
+		t.Errorf("status = %q: %+v", check.Status, check)
+	}
+}
@@ -0,0 +1,80 @@
+// backend/internal/handler/history.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+const (
+	defaultHistoryLimit = 200
+	maxHistoryLimit     = 1000
+)
+
+// 指定日の予測履歴を取得する (GET /api/prediction/history?date=YYYY-MM-DD)
+func (h *Handler) getPredictionHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		http.Error(w, "日付が指定されていません", http.StatusBadRequest)
+		return
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		http.Error(w, "日付の形式が不正です（YYYY-MM-DD）", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > maxHistoryLimit {
+			http.Error(w, "limitは1〜1000で指定してください", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	entries, err := h.history.GetPredictionHistory(date, limit)
+	if err != nil {
+		h.log(r).Error("予測履歴の取得エラー", "date", date, "error", err)
+		http.Error(w, "予測履歴の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.PredictionHistoryResponse{
+		Date:    date,
+		Entries: entries,
+	})
+}
+
+// 予測データの更新ごとの変化を新しい順に取得する (GET /api/prediction/changes?limit=N)
+func (h *Handler) getPredictionChangesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 {
+			http.Error(w, "limitは1以上の整数で指定してください", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.PredictionChangesResponse{
+		Changes: h.cache.PredictionDiffs(limit),
+	})
+}
@@ -0,0 +1,48 @@
+// backend/internal/handler/history_test.go
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+)
+
+// TestPredictionHistoryRejectsInvalidLimit は不正なlimitを400で返すことをテストする（DBには問い合わせない）
+func TestPredictionHistoryRejectsInvalidLimit(t *testing.T) {
+	h := &Handler{}
+	for _, limit := range []string{"abc", "0", "-1", "1001"} {
+		rec := httptest.NewRecorder()
+		h.getPredictionHistoryHandler(rec, httptest.NewRequest(http.MethodGet, "/api/prediction/history?date=2026-04-10&limit="+limit, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("limit=%s: status = %d, want 400", limit, rec.Code)
+		}
+	}
+}
+
+func TestPredictionChangesLimit(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := &Handler{logger: logger, cache: cache.NewCacheManagerWithOptions(logger, provider.NewFixtureSet("../../testdata/fixtures"), cache.Options{})}
+
+	tests := []struct {
+		limit string
+		want  int
+	}{
+		{"", http.StatusOK},
+		{"5", http.StatusOK},
+		{"abc", http.StatusBadRequest},
+		{"0", http.StatusBadRequest},
+		{"-3", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.getPredictionChangesHandler(rec, httptest.NewRequest(http.MethodGet, "/api/prediction/changes?limit="+tt.limit, nil))
+		if rec.Code != tt.want {
+			t.Errorf("limit=%q: status = %d, want %d", tt.limit, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// backend/internal/handler/httpcache.go
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/compress"
+)
+
+// 予報データのキャッシュ有効期間
+// 更新は最短でも1時間ごとなので、ブラウザ・CDNには5分間キャッシュさせ、その後も再検証中は古いデータを返してよいとする
+const (
+	forecastMaxAge               = 5 * time.Minute
+	forecastStaleWhileRevalidate = 10 * time.Minute
+)
+
+// writeCacheEntry はキャッシュエントリをETag・Last-Modified付きで返す
+// If-None-Match / If-Modified-Since が一致する場合は304を返す
+// クライアントが対応していれば、キャッシュ時に圧縮しておいたデータを返す
+func writeCacheEntry(w http.ResponseWriter, r *http.Request, entry *cache.Entry) {
+	body, etag := entry.Data, entry.ETag()
+	if encoding := compress.Negotiate(r.Header.Get("Accept-Encoding")); encoding != "" && r.Header.Get("Range") == "" {
+		if data, encodedETag, ok := entry.Encoded(encoding); ok {
+			body, etag = data, encodedETag
+			w.Header().Set("Content-Encoding", encoding)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag)
+	if entry.Stale {
+		// 前回起動時のスナップショットから復元したデータは、再取得後すぐに差し替わるようキャッシュさせない
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Data-Stale", "true")
+	} else {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d",
+			int(forecastMaxAge.Seconds()), int(forecastStaleWhileRevalidate.Seconds())))
+	}
+	w.Header().Set("X-Data-Age", strconv.Itoa(int(time.Since(entry.FetchedAt).Seconds())))
+	// ServeContentが条件付きリクエストの判定とLast-Modifiedの付与を行う
+	http.ServeContent(w, r, "", entry.FetchedAt, bytes.NewReader(body))
+}
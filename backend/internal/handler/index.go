@@ -0,0 +1,52 @@
+// backend/internal/handler/index.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scoring"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// 指定日の夜の爆湧き指数を取得する (GET /api/index/{date}?spot=...)
+func (h *Handler) getIndexHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	pathSegments := splitPath(r.URL.Path)
+	if len(pathSegments) < 3 || pathSegments[2] == "" {
+		http.Error(w, "日付が指定されていません", http.StatusBadRequest)
+		return
+	}
+	dateStr := pathSegments[2]
+	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
+		http.Error(w, "日付の形式が不正です（YYYY-MM-DD）", http.StatusBadRequest)
+		return
+	}
+	spotID := r.URL.Query().Get("spot")
+	if spotID == "" {
+		spotID = spot.DefaultID
+	}
+	if _, ok := spot.Get(spotID); !ok {
+		http.Error(w, "指定された地点は存在しません", http.StatusBadRequest)
+		return
+	}
+
+	index, err := h.scoring.Compute(spotID, dateStr)
+	if errors.Is(err, scoring.ErrNoPrediction) {
+		http.Error(w, "指定された日付のデータは見つかりません", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.log(r).Error("爆湧き指数の算出エラー", "date", dateStr, "spot", spotID, "error", err)
+		http.Error(w, "爆湧き指数の算出に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(index)
+}
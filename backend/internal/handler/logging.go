@@ -0,0 +1,146 @@
+// backend/internal/handler/logging.go
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// リクエストIDを受け渡しするヘッダー
+const requestIDHeader = "X-Request-ID"
+
+// 受け取ったリクエストIDをそのまま使う最大の長さ
+const maxRequestIDLength = 64
+
+// 死活監視・メトリクスの収集は頻繁に来るので、アクセスログをDEBUGで出す
+var quietRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+type loggerKey struct{}
+
+// AccessLogMiddleware はリクエストごとにIDを付け、アクセスログを出力する
+// X-Request-IDが付いていればそれを引き継ぎ、なければ新しく発行してレスポンスにも付ける
+// ハンドラではh.log(r)でリクエストIDの付いたロガーを使う
+func (h *Handler) AccessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		logger := h.logger.With("request_id", requestID)
+		r = r.WithContext(context.WithValue(r.Context(), loggerKey{}, logger))
+		rec := &accessRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		defer func() {
+			if p := recover(); p != nil {
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				logger.Error("リクエストの処理中にパニックが発生しました", "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+				if !rec.wroteHeader {
+					http.Error(rec, "内部サーバーエラー", http.StatusInternalServerError)
+				}
+			}
+
+			// ServeMuxがルーティング時にr.Patternを設定する
+			route := r.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+			level := slog.LevelInfo
+			switch {
+			case rec.status >= 500:
+				level = slog.LevelError
+			case quietRoutes[route]:
+				level = slog.LevelDebug
+			}
+			logger.LogAttrs(r.Context(), level, "リクエスト",
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.Int64("bytes", rec.bytes),
+				slog.String("client_ip", getClientIP(r)),
+				slog.String("device", h.displayID(r.Header.Get("X-Device-ID"))),
+			)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// log はリクエストIDの付いたロガーを返す（AccessLogMiddlewareを通っていない場合はh.logger）
+func (h *Handler) log(r *http.Request) *slog.Logger {
+	if logger, ok := r.Context().Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return h.logger
+}
+
+// validRequestID はログやヘッダーにそのまま載せてよいIDかを返す（英数字と - _ . のみ）
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// accessRecorder はハンドラが返したステータスコードと送ったバイト数を記録する
+type accessRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (r *accessRecorder) WriteHeader(code int) {
+	if !r.wroteHeader && code >= 200 {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *accessRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *accessRecorder) Flush() {
+	r.wroteHeader = true
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap はhttp.ResponseControllerから元のResponseWriterを使えるようにする
+func (r *accessRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,77 @@
+// backend/internal/handler/night.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// 指定日の夜（既定では20時〜翌5時）の条件のまとめを取得する (GET /api/night/{date}?spot=...)
+func (h *Handler) getNightHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	pathSegments := splitPath(r.URL.Path)
+	if len(pathSegments) < 3 || pathSegments[2] == "" {
+		http.Error(w, "日付が指定されていません", http.StatusBadRequest)
+		return
+	}
+	dateStr := pathSegments[2]
+	spotID := r.URL.Query().Get("spot")
+	if spotID == "" {
+		spotID = spot.DefaultID
+	}
+	if _, ok := spot.Get(spotID); !ok {
+		http.Error(w, "指定された地点は存在しません", http.StatusBadRequest)
+		return
+	}
+	detail, ok := h.cache.GetDetail(spotID, dateStr)
+	if !ok {
+		http.Error(w, "指定された日付のデータは見つかりません", http.StatusNotFound)
+		return
+	}
+	summary, err := night.Summarize(h.night, detail)
+	if err != nil {
+		h.log(r).Error("夜間のまとめの作成エラー", "date", dateStr, "spot", spotID, "error", err)
+		http.Error(w, "夜間のまとめの作成に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// 指定日の夜の時間ごとの掬いやすさを取得する (GET /api/detail/{date}/timeline?spot=...)
+func (h *Handler) getTimelineHandler(w http.ResponseWriter, r *http.Request, dateStr string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	spotID := r.URL.Query().Get("spot")
+	if spotID == "" {
+		spotID = spot.DefaultID
+	}
+	s, ok := spot.Get(spotID)
+	if !ok {
+		http.Error(w, "指定された地点は存在しません", http.StatusBadRequest)
+		return
+	}
+	detail, ok := h.cache.GetDetail(spotID, dateStr)
+	if !ok {
+		http.Error(w, "指定された日付のデータは見つかりません", http.StatusNotFound)
+		return
+	}
+	timeline, err := night.BuildTimeline(h.night, h.scoring.Config().TimelineWeights(), h.scoring.Config().TimelineWind(), detail, s.ShoreBearing)
+	if err != nil {
+		h.log(r).Error("タイムラインの作成エラー", "date", dateStr, "spot", spotID, "error", err)
+		http.Error(w, "タイムラインの作成に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(timeline)
+}
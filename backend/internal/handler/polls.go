@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/lib/pq"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/metrics"
 	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
 )
 
@@ -54,7 +55,7 @@ func (h *Handler) votePollOption(w http.ResponseWriter, r *http.Request, optionI
 		return
 	}
 	if err != nil {
-		h.logger.Error("投票前の確認エラー", "error", err)
+		h.log(r).Error("投票前の確認エラー", "error", err)
 		http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
 		return
 	}
@@ -67,29 +68,30 @@ func (h *Handler) votePollOption(w http.ResponseWriter, r *http.Request, optionI
 	// トランザクションで投票カウントをインクリメント
 	tx, err := h.db.Begin()
 	if err != nil {
-		h.logger.Error("トランザクション開始エラー", "error", err)
+		h.log(r).Error("トランザクション開始エラー", "error", err)
 		http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
 		return
 	}
 	defer tx.Rollback()
 
 	if _, err := tx.Exec(`UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = $1`, optionID); err != nil {
-		h.logger.Error("投票カウント更新エラー", "error", err)
+		h.log(r).Error("投票カウント更新エラー", "error", err)
 		http.Error(w, "投票に失敗しました", http.StatusInternalServerError)
 		return
 	}
 
 	if _, err := tx.Exec(`UPDATE polls SET total_votes = total_votes + 1 WHERE id = $1`, pollID); err != nil {
-		h.logger.Error("総投票数更新エラー", "error", err)
+		h.log(r).Error("総投票数更新エラー", "error", err)
 		http.Error(w, "投票に失敗しました", http.StatusInternalServerError)
 		return
 	}
 
 	if err := tx.Commit(); err != nil {
-		h.logger.Error("トランザクションコミットエラー", "error", err)
+		h.log(r).Error("トランザクションコミットエラー", "error", err)
 		http.Error(w, "投票に失敗しました", http.StatusInternalServerError)
 		return
 	}
+	metrics.ContentCreated.WithLabelValues(metrics.ContentVote).Inc()
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
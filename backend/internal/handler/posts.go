@@ -7,9 +7,9 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -20,8 +20,10 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/lib/pq"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/metrics"
 	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
-	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/storage"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/sighting"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/webhook"
 )
 
 // findDeviceIDsByDisplayID はdisplay_idに部分一致するdevice_idをDBから検索する
@@ -39,7 +41,7 @@ func (h *Handler) findDeviceIDsByDisplayID(displayIDFilter string) ([]string, er
 		if err := rows.Scan(&deviceID); err != nil {
 			continue
 		}
-		did := generateDisplayID(deviceID)
+		did := h.displayID(deviceID)
 		if strings.Contains(strings.ToLower(did), filterLower) {
 			matched = append(matched, deviceID)
 		}
@@ -47,13 +49,12 @@ func (h *Handler) findDeviceIDsByDisplayID(displayIDFilter string) ([]string, er
 	return matched, nil
 }
 
-// generateDisplayID はdevice_idからSHA-256ハッシュで7文字の表示用IDを生成する
-func generateDisplayID(deviceID string) string {
+// displayID はdevice_idからSHA-256ハッシュで7文字の表示用IDを生成する
+func (h *Handler) displayID(deviceID string) string {
 	if deviceID == "" {
 		return ""
 	}
-	salt := os.Getenv("DISPLAY_ID_SALT")
-	hash := sha256.Sum256([]byte(deviceID + salt))
+	hash := sha256.Sum256([]byte(deviceID + h.auth.DisplayIDSalt))
 	return hex.EncodeToString(hash[:])[:7]
 }
 
@@ -88,14 +89,21 @@ const (
 	globalReactLimit = 100 // 全体で1分間に100リアクションまで
 )
 
-func init() {
-	// 5分ごとにレートマップの期限切れエントリを削除
-	go func() {
-		for {
-			time.Sleep(5 * time.Minute)
+// レートマップの期限切れエントリを削除する間隔
+const rateMapCleanupInterval = 5 * time.Minute
+
+// runRateMapCleanup はstopが閉じられるまで定期的にレートマップの期限切れエントリを削除する
+func runRateMapCleanup(stop <-chan struct{}) {
+	ticker := time.NewTicker(rateMapCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
 			cleanupRateMaps()
+		case <-stop:
+			return
 		}
-	}()
+	}
 }
 
 func cleanupRateMaps() {
@@ -215,6 +223,7 @@ func checkDeviceCooldown(w http.ResponseWriter, r *http.Request) bool {
 
 	if lastPost, exists := deviceLastPostMap[deviceID]; exists {
 		if now.Sub(lastPost) < devicePostCooldown {
+			metrics.RateLimitRejections.WithLabelValues("post", "device").Inc()
 			http.Error(w, "連続投稿はできません。しばらく時間をおいてください", http.StatusTooManyRequests)
 			return false
 		}
@@ -227,10 +236,12 @@ func checkDeviceCooldown(w http.ResponseWriter, r *http.Request) bool {
 // 制限に引っかかった場合はエラーレスポンスを返してfalseを返す
 func checkPostRateLimit(w http.ResponseWriter, r *http.Request) bool {
 	if !checkGlobalRate(&globalPostTimes, globalPostLimit, time.Minute) {
+		metrics.RateLimitRejections.WithLabelValues("post", "global").Inc()
 		http.Error(w, "現在投稿が集中しています。しばらく待ってください", http.StatusTooManyRequests)
 		return false
 	}
 	if !checkRate(postRateMap, getClientIP(r), 5, time.Minute) {
+		metrics.RateLimitRejections.WithLabelValues("post", "ip").Inc()
 		http.Error(w, "投稿が多すぎます。しばらく待ってください", http.StatusTooManyRequests)
 		return false
 	}
@@ -240,10 +251,12 @@ func checkPostRateLimit(w http.ResponseWriter, r *http.Request) bool {
 // グローバル制限+IPベース制限を一括チェックする（リアクション/投票用）
 func checkReactRateLimit(w http.ResponseWriter, r *http.Request) bool {
 	if !checkGlobalRate(&globalReactTimes, globalReactLimit, time.Minute) {
+		metrics.RateLimitRejections.WithLabelValues("react", "global").Inc()
 		http.Error(w, "現在リアクションが集中しています。しばらく待ってください", http.StatusTooManyRequests)
 		return false
 	}
 	if !checkRate(reactRateMap, getClientIP(r), 10, time.Minute) {
+		metrics.RateLimitRejections.WithLabelValues("react", "ip").Inc()
 		http.Error(w, "リアクションが多すぎます。しばらく待ってください", http.StatusTooManyRequests)
 		return false
 	}
@@ -428,9 +441,21 @@ func (h *Handler) createPost(w http.ResponseWriter, r *http.Request, isAdmin boo
 		}
 	}
 
+	// 目撃報告のバリデーション（現地情報の投稿にのみ添えられる）
+	if post.SightingRequest != nil {
+		if post.Label != "現地情報" {
+			http.Error(w, "目撃報告は現地情報の投稿にのみ添えられます", http.StatusBadRequest)
+			return
+		}
+		if err := sighting.Validate(post.SightingRequest, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	imageURLs, err := h.uploadBase64Images(post.ImageURLs)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), imageUploadStatus(err))
 		return
 	}
 
@@ -460,7 +485,7 @@ func (h *Handler) createPost(w http.ResponseWriter, r *http.Request, isAdmin boo
 	// トランザクションで投稿+アンケートを一括作成
 	tx, err := h.db.Begin()
 	if err != nil {
-		h.logger.Error("トランザクション開始エラー", "error", err)
+		h.log(r).Error("トランザクション開始エラー", "error", err)
 		http.Error(w, "投稿の作成に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -469,7 +494,7 @@ func (h *Handler) createPost(w http.ResponseWriter, r *http.Request, isAdmin boo
 	deviceID := r.Header.Get("X-Device-ID")
 	query := `INSERT INTO posts (username, content, image_urls, label, device_id) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
 	if err := tx.QueryRow(query, post.Username, post.Content, pq.Array(imageURLs), post.Label, deviceID).Scan(&post.ID, &post.CreatedAt); err != nil {
-		h.logger.Error("投稿の挿入エラー", "error", err)
+		h.log(r).Error("投稿の挿入エラー", "error", err)
 		http.Error(w, "投稿の作成に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -482,7 +507,7 @@ func (h *Handler) createPost(w http.ResponseWriter, r *http.Request, isAdmin boo
 		var poll model.Poll
 		pollQuery := `INSERT INTO polls (post_id, expires_at) VALUES ($1, $2) RETURNING id, created_at, total_votes`
 		if err := tx.QueryRow(pollQuery, post.ID, expiresAt).Scan(&poll.ID, &poll.CreatedAt, &poll.TotalVotes); err != nil {
-			h.logger.Error("アンケートの挿入エラー", "error", err)
+			h.log(r).Error("アンケートの挿入エラー", "error", err)
 			http.Error(w, "アンケートの作成に失敗しました", http.StatusInternalServerError)
 			return
 		}
@@ -493,7 +518,7 @@ func (h *Handler) createPost(w http.ResponseWriter, r *http.Request, isAdmin boo
 			var option model.PollOption
 			optQuery := `INSERT INTO poll_options (poll_id, option_text, display_order) VALUES ($1, $2, $3) RETURNING id, vote_count`
 			if err := tx.QueryRow(optQuery, poll.ID, optText, i).Scan(&option.ID, &option.VoteCount); err != nil {
-				h.logger.Error("選択肢の挿入エラー", "error", err)
+				h.log(r).Error("選択肢の挿入エラー", "error", err)
 				http.Error(w, "選択肢の作成に失敗しました", http.StatusInternalServerError)
 				return
 			}
@@ -505,14 +530,30 @@ func (h *Handler) createPost(w http.ResponseWriter, r *http.Request, isAdmin boo
 		post.Poll = &poll
 	}
 
+	// 目撃報告の登録（オプション）
+	if post.SightingRequest != nil {
+		s, err := insertSighting(tx, post.ID, post.SightingRequest)
+		if err != nil {
+			h.log(r).Error("目撃報告の挿入エラー", "error", err)
+			http.Error(w, "目撃報告の登録に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		post.Sighting = s
+	}
+
 	if err := tx.Commit(); err != nil {
-		h.logger.Error("トランザクションコミットエラー", "error", err)
+		h.log(r).Error("トランザクションコミットエラー", "error", err)
 		http.Error(w, "投稿の作成に失敗しました", http.StatusInternalServerError)
 		return
 	}
 
 	post.ImageURLs = imageURLs
 	post.PollRequest = nil // レスポンスには含めない
+	post.SightingRequest = nil
+	metrics.ContentCreated.WithLabelValues(metrics.ContentPost).Inc()
+	if post.Label == "現地情報" {
+		h.webhooks.Dispatch(webhook.PostEvent(webhook.EventFieldReport, post, h.siteURL))
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(post)
@@ -601,7 +642,7 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 			// display_id（ハッシュ値）からdevice_idを逆引きして検索（投稿・返信両方を対象）
 			matchedDeviceIDs, err := h.findDeviceIDsByDisplayID(deviceIDFilter)
 			if err != nil {
-				h.logger.Error("display_idからdevice_idの検索エラー", "error", err)
+				h.log(r).Error("display_idからdevice_idの検索エラー", "error", err)
 			}
 			if len(matchedDeviceIDs) == 0 {
 				// 一致するdevice_idがない場合、結果を空にする
@@ -644,7 +685,7 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 		if usePagination {
 			countQuery := "SELECT COUNT(*) FROM posts p" + whereClause
 			if err := h.db.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
-				h.logger.Error("投稿数のカウントエラー", "error", err)
+				h.log(r).Error("投稿数のカウントエラー", "error", err)
 				return err
 			}
 		}
@@ -664,7 +705,7 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 
 		rows, err := h.db.Query(query, args...)
 		if err != nil {
-			h.logger.Error("投稿のクエリエラー（リトライ中）", "error", err)
+			h.log(r).Error("投稿のクエリエラー（リトライ中）", "error", err)
 			return err
 		}
 		defer rows.Close()
@@ -674,11 +715,11 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 			var post model.Post
 			var deviceID sql.NullString
 			if err := rows.Scan(&post.ID, &post.Username, &post.Content, pq.Array(&post.ImageURLs), &post.Label, &post.CreatedAt, &post.GoodCount, &post.BadCount, &deviceID, &post.IsPinned); err != nil {
-				h.logger.Error("投稿行のスキャンエラー", "error", err)
+				h.log(r).Error("投稿行のスキャンエラー", "error", err)
 				continue
 			}
 			if deviceID.Valid && deviceID.String != "" {
-				did := generateDisplayID(deviceID.String)
+				did := h.displayID(deviceID.String)
 				post.DisplayID = &did
 				if includeDeviceID {
 					post.DeviceID = &deviceID.String
@@ -692,7 +733,7 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 	err := backoff.Retry(operation, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3))
 
 	if err != nil {
-		h.logger.Error("投稿の取得に失敗しました（リトライ上限到達）", "error", err)
+		h.log(r).Error("投稿の取得に失敗しました（リトライ上限到達）", "error", err)
 		http.Error(w, "投稿の取得に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -705,7 +746,7 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 		}
 		pollsMap, pollErr := h.getPollsForPosts(postIDs)
 		if pollErr != nil {
-			h.logger.Error("アンケートの取得に失敗しました", "error", pollErr)
+			h.log(r).Error("アンケートの取得に失敗しました", "error", pollErr)
 			// 致命的ではないのでアンケートなしで続行
 		} else {
 			for i := range posts {
@@ -714,6 +755,16 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 		}
+
+		sightingsMap, sightingErr := h.getSightingsForPosts(postIDs)
+		if sightingErr != nil {
+			h.log(r).Error("目撃報告の取得に失敗しました", "error", sightingErr)
+			// 致命的ではないので目撃報告なしで続行
+		} else {
+			for i := range posts {
+				posts[i].Sighting = sightingsMap[posts[i].ID]
+			}
+		}
 	}
 
 	// include=replies の場合
@@ -728,7 +779,7 @@ func (h *Handler) getPosts(w http.ResponseWriter, r *http.Request) {
 
 			repliesMap, err := h.getAllRepliesForPosts(postIDs, includeDeviceID)
 			if err != nil {
-				h.logger.Error("返信の一括取得に失敗しました", "error", err)
+				h.log(r).Error("返信の一括取得に失敗しました", "error", err)
 				http.Error(w, "返信の取得に失敗しました", http.StatusInternalServerError)
 				return
 			}
@@ -822,7 +873,7 @@ func (h *Handler) getAllRepliesForPosts(postIDs []int, includeDeviceID bool) (ma
 			reply.Label = &label.String
 		}
 		if deviceID.Valid && deviceID.String != "" {
-			did := generateDisplayID(deviceID.String)
+			did := h.displayID(deviceID.String)
 			reply.DisplayID = &did
 			if includeDeviceID {
 				reply.DeviceID = &deviceID.String
@@ -834,7 +885,7 @@ func (h *Handler) getAllRepliesForPosts(postIDs []int, includeDeviceID bool) (ma
 	return repliesMap, rows.Err()
 }
 
-func (h *Handler) getRepliesForPost(w http.ResponseWriter, _ *http.Request, postID int) {
+func (h *Handler) getRepliesForPost(w http.ResponseWriter, r *http.Request, postID int) {
 	var replies []model.Reply
 
 	operation := func() error {
@@ -842,7 +893,7 @@ func (h *Handler) getRepliesForPost(w http.ResponseWriter, _ *http.Request, post
 
 		rows, err := h.db.Query(query, postID)
 		if err != nil {
-			h.logger.Error("返信のクエリエラー（リトライ中）", "error", err)
+			h.log(r).Error("返信のクエリエラー（リトライ中）", "error", err)
 			return err
 		}
 		defer rows.Close()
@@ -855,7 +906,7 @@ func (h *Handler) getRepliesForPost(w http.ResponseWriter, _ *http.Request, post
 			var label sql.NullString
 			var deviceID sql.NullString
 			if err := rows.Scan(&reply.ID, &reply.PostID, &parentReplyID, &reply.Username, &reply.Content, pq.Array(&reply.ImageURLs), &label, &reply.CreatedAt, &reply.GoodCount, &reply.BadCount, &parentUsername, &deviceID); err != nil {
-				h.logger.Error("返信行のスキャンエラー", "error", err)
+				h.log(r).Error("返信行のスキャンエラー", "error", err)
 				continue
 			}
 			if parentReplyID.Valid {
@@ -869,7 +920,7 @@ func (h *Handler) getRepliesForPost(w http.ResponseWriter, _ *http.Request, post
 				reply.Label = &label.String
 			}
 			if deviceID.Valid && deviceID.String != "" {
-				did := generateDisplayID(deviceID.String)
+				did := h.displayID(deviceID.String)
 				reply.DisplayID = &did
 			}
 			replies = append(replies, reply)
@@ -880,7 +931,7 @@ func (h *Handler) getRepliesForPost(w http.ResponseWriter, _ *http.Request, post
 	err := backoff.Retry(operation, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3))
 
 	if err != nil {
-		h.logger.Error("返信の取得に失敗しました（リトライ上限到達）", "error", err)
+		h.log(r).Error("返信の取得に失敗しました（リトライ上限到達）", "error", err)
 		http.Error(w, "返信の取得に失敗しました", http.StatusInternalServerError)
 		return
 	}
@@ -945,7 +996,7 @@ func (h *Handler) createReplyToPost(w http.ResponseWriter, r *http.Request, post
 
 	imageURLs, err := h.uploadBase64Images(reply.ImageURLs)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), imageUploadStatus(err))
 		return
 	}
 
@@ -953,12 +1004,13 @@ func (h *Handler) createReplyToPost(w http.ResponseWriter, r *http.Request, post
 	query := `INSERT INTO replies (post_id, username, content, label, image_urls, device_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
 	err = h.db.QueryRow(query, postID, reply.Username, reply.Content, reply.Label, pq.Array(imageURLs), deviceID).Scan(&reply.ID, &reply.CreatedAt)
 	if err != nil {
-		h.logger.Error("投稿への返信エラー", "error", err)
+		h.log(r).Error("投稿への返信エラー", "error", err)
 		http.Error(w, "返信できませんでした", http.StatusInternalServerError)
 		return
 	}
 	reply.PostID = postID
 	reply.ImageURLs = imageURLs
+	metrics.ContentCreated.WithLabelValues(metrics.ContentReply).Inc()
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(reply)
@@ -1022,14 +1074,14 @@ func (h *Handler) createReplyToReply(w http.ResponseWriter, r *http.Request, par
 	var postID int
 	err := h.db.QueryRow("SELECT post_id FROM replies WHERE id = $1", parentReplyID).Scan(&postID)
 	if err != nil {
-		h.logger.Error("親返信の取得エラー", "error", err)
+		h.log(r).Error("親返信の取得エラー", "error", err)
 		http.Error(w, "返信できませんでした", http.StatusNotFound)
 		return
 	}
 
 	imageURLs, imgErr := h.uploadBase64Images(reply.ImageURLs)
 	if imgErr != nil {
-		http.Error(w, imgErr.Error(), http.StatusInternalServerError)
+		http.Error(w, imgErr.Error(), imageUploadStatus(imgErr))
 		return
 	}
 
@@ -1037,13 +1089,14 @@ func (h *Handler) createReplyToReply(w http.ResponseWriter, r *http.Request, par
 	query := `INSERT INTO replies (post_id, parent_reply_id, username, content, label, image_urls, device_id) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
 	err = h.db.QueryRow(query, postID, parentReplyID, reply.Username, reply.Content, reply.Label, pq.Array(imageURLs), deviceID).Scan(&reply.ID, &reply.CreatedAt)
 	if err != nil {
-		h.logger.Error("返信への返信エラー", "error", err)
+		h.log(r).Error("返信への返信エラー", "error", err)
 		http.Error(w, "返信できませんでした", http.StatusInternalServerError)
 		return
 	}
 	reply.PostID = postID
 	reply.ParentReplyID = &parentReplyID
 	reply.ImageURLs = imageURLs
+	metrics.ContentCreated.WithLabelValues(metrics.ContentReply).Inc()
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(reply)
@@ -1062,11 +1115,12 @@ func (h *Handler) createPostReaction(w http.ResponseWriter, r *http.Request, pos
 	query := `INSERT INTO reactions (post_id, reaction_type) VALUES ($1, $2) RETURNING id, created_at`
 	err := h.db.QueryRow(query, postID, reaction.ReactionType).Scan(&reaction.ID, &reaction.CreatedAt)
 	if err != nil {
-		h.logger.Error("投稿へのリアクションエラー", "error", err)
+		h.log(r).Error("投稿へのリアクションエラー", "error", err)
 		http.Error(w, "リアクションできませんでした", http.StatusInternalServerError)
 		return
 	}
 	reaction.PostID = &postID
+	metrics.ContentCreated.WithLabelValues(metrics.ContentReaction).Inc()
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(reaction)
@@ -1085,18 +1139,36 @@ func (h *Handler) createReplyReaction(w http.ResponseWriter, r *http.Request, re
 	query := `INSERT INTO reactions (reply_id, reaction_type) VALUES ($1, $2) RETURNING id, created_at`
 	err := h.db.QueryRow(query, replyID, reaction.ReactionType).Scan(&reaction.ID, &reaction.CreatedAt)
 	if err != nil {
-		h.logger.Error("返信へのリアクションエラー", "error", err)
+		h.log(r).Error("返信へのリアクションエラー", "error", err)
 		http.Error(w, "リアクションできませんでした", http.StatusInternalServerError)
 		return
 	}
 	reaction.ReplyID = &replyID
+	metrics.ContentCreated.WithLabelValues(metrics.ContentReaction).Inc()
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(reaction)
 }
 
+// 投稿画像を保存するSupabase Storageのバケット
+const postImageBucket = "post-images"
+
+// errImageStorageDisabled は画像の保存先を無効にしている（STORAGE_DISABLED）ため画像を投稿できない場合のエラー
+var errImageStorageDisabled = errors.New("画像付きの投稿は現在受け付けていません")
+
+// imageUploadStatus は画像のアップロードに失敗した場合のステータスコードを返す
+func imageUploadStatus(err error) int {
+	if errors.Is(err, errImageStorageDisabled) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
 // uploadBase64Images はBase64エンコードされた画像データをSupabase Storageにアップロードし、公開URLの配列を返す
 func (h *Handler) uploadBase64Images(dataURLs []string) ([]string, error) {
+	if len(dataURLs) > 0 && h.storage == nil {
+		return nil, errImageStorageDisabled
+	}
 	var imageURLs []string
 	for _, dataURL := range dataURLs {
 		parts := strings.SplitN(dataURL, ";base64,", 2)
@@ -1113,17 +1185,16 @@ func (h *Handler) uploadBase64Images(dataURLs []string) ([]string, error) {
 			return nil, fmt.Errorf("画像のデコードに失敗しました")
 		}
 		filename := fmt.Sprintf("%d.%s", time.Now().UnixNano(), extension)
-		if err := storage.UploadFileToSupabase(h.logger, "post-images", filename, decoded, mimeType); err != nil {
+		if err := h.storage.Upload(postImageBucket, filename, decoded, mimeType); err != nil {
 			h.logger.Error("画像のアップロードに失敗しました", "error", err)
 			return nil, fmt.Errorf("画像のアップロードに失敗しました")
 		}
-		publicURL := fmt.Sprintf("%s/storage/v1/object/public/post-images/%s", os.Getenv("SUPABASE_URL"), filename)
-		imageURLs = append(imageURLs, publicURL)
+		imageURLs = append(imageURLs, h.storage.PublicURL(postImageBucket, filename))
 	}
 	return imageURLs, nil
 }
 
-func (h *Handler) deleteItem(w http.ResponseWriter, _ *http.Request, itemType string, itemID int) {
+func (h *Handler) deleteItem(w http.ResponseWriter, r *http.Request, itemType string, itemID int) {
 	var tableName, imageColumn string
 	if itemType == "posts" {
 		tableName, imageColumn = "posts", "image_urls"
@@ -1136,7 +1207,7 @@ func (h *Handler) deleteItem(w http.ResponseWriter, _ *http.Request, itemType st
 
 	tx, err := h.db.Begin()
 	if err != nil {
-		h.logger.Error("トランザクション開始エラー", "error", err)
+		h.log(r).Error("トランザクション開始エラー", "error", err)
 		http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
 		return
 	}
@@ -1148,7 +1219,7 @@ func (h *Handler) deleteItem(w http.ResponseWriter, _ *http.Request, itemType st
 		var imageURLs pq.StringArray
 		query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", imageColumn, tableName)
 		if err := tx.QueryRow(query, itemID).Scan(&imageURLs); err != nil && err != sql.ErrNoRows {
-			h.logger.Error("画像URLのクエリエラー", "error", err)
+			h.log(r).Error("画像URLのクエリエラー", "error", err)
 			http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
 			return
 		}
@@ -1174,21 +1245,21 @@ func (h *Handler) deleteItem(w http.ResponseWriter, _ *http.Request, itemType st
 	}
 
 	// Supabase Storageから画像を削除
-	if len(allFileNames) > 0 {
-		if err := storage.DeleteFileFromSupabase(h.logger, "post-images", allFileNames); err != nil {
-			h.logger.Warn("Supabaseからの画像ファイル削除に失敗しました", "filenames", allFileNames, "error", err)
+	if len(allFileNames) > 0 && h.storage != nil {
+		if err := h.storage.Delete(postImageBucket, allFileNames); err != nil {
+			h.log(r).Warn("Supabaseからの画像ファイル削除に失敗しました", "filenames", allFileNames, "error", err)
 		}
 	}
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
 	if _, err := tx.Exec(query, itemID); err != nil {
-		h.logger.Error("DBからのアイテム削除エラー", "error", err)
+		h.log(r).Error("DBからのアイテム削除エラー", "error", err)
 		http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
 		return
 	}
 
 	if err := tx.Commit(); err != nil {
-		h.logger.Error("トランザクションのコミットエラー", "error", err)
+		h.log(r).Error("トランザクションのコミットエラー", "error", err)
 		http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
 		return
 	}
@@ -1210,24 +1281,47 @@ func (h *Handler) updatePostLabel(w http.ResponseWriter, r *http.Request, postID
 		return
 	}
 
-	query := `UPDATE posts SET label = $1 WHERE id = $2`
-	result, err := h.db.Exec(query, req.Label, postID)
+	// 目撃報告は現地情報の投稿にのみ添えられるため、他のラベルに変えた場合は同じトランザクションで削除する
+	tx, err := h.db.Begin()
 	if err != nil {
-		h.logger.Error("ラベルの更新エラー", "error", err)
+		h.log(r).Error("トランザクション開始エラー", "error", err)
 		http.Error(w, "ラベルの更新に失敗しました", http.StatusInternalServerError)
 		return
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	// 他のラベルから現地情報に変えたときはWebhookに通知するため、更新前のラベルも取得する
+	query := `UPDATE posts p SET label = $1
+		FROM (SELECT id, label FROM posts WHERE id = $2 FOR UPDATE) old
+		WHERE p.id = old.id
+		RETURNING old.label, p.id, p.username, p.content, p.image_urls, p.label, p.created_at, p.is_pinned`
+	var oldLabel string
+	var post model.Post
+	err = tx.QueryRow(query, req.Label, postID).Scan(&oldLabel, &post.ID, &post.Username, &post.Content, pq.Array(&post.ImageURLs), &post.Label, &post.CreatedAt, &post.IsPinned)
+	if err == sql.ErrNoRows {
+		http.Error(w, "投稿が見つかりません", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		h.logger.Error("RowsAffectedの取得エラー", "error", err)
-		http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
+		h.log(r).Error("ラベルの更新エラー", "error", err)
+		http.Error(w, "ラベルの更新に失敗しました", http.StatusInternalServerError)
 		return
 	}
-	if rowsAffected == 0 {
-		http.Error(w, "投稿が見つかりません", http.StatusNotFound)
+	if req.Label != "現地情報" {
+		if _, err := tx.Exec(`DELETE FROM sightings WHERE post_id = $1`, postID); err != nil {
+			h.log(r).Error("目撃報告の削除エラー", "error", err)
+			http.Error(w, "ラベルの更新に失敗しました", http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		h.log(r).Error("トランザクションコミットエラー", "error", err)
+		http.Error(w, "ラベルの更新に失敗しました", http.StatusInternalServerError)
 		return
 	}
+	if req.Label == "現地情報" && oldLabel != "現地情報" {
+		h.webhooks.Dispatch(webhook.PostEvent(webhook.EventFieldReport, post, h.siteURL))
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"label": req.Label})
@@ -1242,23 +1336,26 @@ func (h *Handler) updatePostPin(w http.ResponseWriter, r *http.Request, postID i
 		return
 	}
 
-	query := `UPDATE posts SET is_pinned = $1 WHERE id = $2`
-	result, err := h.db.Exec(query, req.IsPinned, postID)
-	if err != nil {
-		h.logger.Error("固定状態の更新エラー", "error", err)
-		http.Error(w, "固定状態の更新に失敗しました", http.StatusInternalServerError)
+	// 固定されていなかった投稿が固定されたときはWebhookに通知するため、更新前の状態も取得する
+	query := `UPDATE posts p SET is_pinned = $1
+		FROM (SELECT id, is_pinned FROM posts WHERE id = $2 FOR UPDATE) old
+		WHERE p.id = old.id
+		RETURNING old.is_pinned, p.id, p.username, p.content, p.image_urls, p.label, p.created_at`
+	var wasPinned bool
+	var post model.Post
+	err := h.db.QueryRow(query, req.IsPinned, postID).Scan(&wasPinned, &post.ID, &post.Username, &post.Content, pq.Array(&post.ImageURLs), &post.Label, &post.CreatedAt)
+	if err == sql.ErrNoRows {
+		http.Error(w, "投稿が見つかりません", http.StatusNotFound)
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		h.logger.Error("RowsAffectedの取得エラー", "error", err)
-		http.Error(w, "内部サーバーエラー", http.StatusInternalServerError)
+		h.log(r).Error("固定状態の更新エラー", "error", err)
+		http.Error(w, "固定状態の更新に失敗しました", http.StatusInternalServerError)
 		return
 	}
-	if rowsAffected == 0 {
-		http.Error(w, "投稿が見つかりません", http.StatusNotFound)
-		return
+	if req.IsPinned && !wasPinned {
+		post.IsPinned = true
+		h.webhooks.Dispatch(webhook.PostEvent(webhook.EventPinnedPost, post, h.siteURL))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
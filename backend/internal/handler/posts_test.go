@@ -0,0 +1,25 @@
+// backend/internal/handler/posts_test.go
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCreatePostWithImageStorageDisabled は画像の保存先を無効にしている場合に画像付きの投稿を503で断ることをテストする
+func TestCreatePostWithImageStorageDisabled(t *testing.T) {
+	h, mock := newSightingTestHandler(t)
+
+	body := `{"username":"テスト","content":"岩瀬浜","label":"その他","image_urls":["data:image/png;base64,iVBORw0KGgo="]}`
+	rec := httptest.NewRecorder()
+	h.createPost(rec, httptest.NewRequest(http.MethodPost, "/api/posts", strings.NewReader(body)), false)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503, body = %s", rec.Code, rec.Body)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
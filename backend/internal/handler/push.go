@@ -0,0 +1,154 @@
+// backend/internal/handler/push.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/push"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// 購読登録リクエストの本文の上限
+const maxPushRequestBytes = 8 << 10
+
+// pushSubscriptionRequest はブラウザのPushSubscription.toJSON()と通知条件
+type pushSubscriptionRequest struct {
+	Subscription struct {
+		Endpoint string `json:"endpoint"`
+		Keys     struct {
+			P256dh string `json:"p256dh"`
+			Auth   string `json:"auth"`
+		} `json:"keys"`
+	} `json:"subscription"`
+	MinLevel      *int     `json:"min_level"`
+	JumpThreshold *float64 `json:"jump_threshold"`
+	Spots         []string `json:"spots"`
+}
+
+// VAPID公開鍵を取得する (GET /api/push/vapid-public-key)
+func (h *Handler) getVAPIDPublicKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.vapid == nil {
+		http.Error(w, "プッシュ通知は現在利用できません", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"public_key": h.vapid.PublicKey()})
+}
+
+// プッシュ通知の購読を取得・登録・解除する (/api/push/subscription)
+// 購読はX-Device-IDヘッダーのデバイスIDごとに1件
+func (h *Handler) pushSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	if h.vapid == nil {
+		http.Error(w, "プッシュ通知は現在利用できません", http.StatusServiceUnavailable)
+		return
+	}
+	deviceID := r.Header.Get("X-Device-ID")
+	if deviceID == "" {
+		http.Error(w, "デバイスIDが指定されていません", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		sub, err := h.push.Get(deviceID)
+		if err != nil {
+			h.log(r).Error("プッシュ通知の購読取得エラー", "error", err)
+			http.Error(w, "購読情報の取得に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		if sub == nil {
+			http.Error(w, "購読は登録されていません", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sub)
+	case http.MethodPost, http.MethodPut:
+		h.savePushSubscription(w, r, deviceID)
+	case http.MethodDelete:
+		deleted, err := h.push.Delete(deviceID)
+		if err != nil {
+			h.log(r).Error("プッシュ通知の購読解除エラー", "error", err)
+			http.Error(w, "購読の解除に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		if !deleted {
+			http.Error(w, "購読は登録されていません", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) savePushSubscription(w http.ResponseWriter, r *http.Request, deviceID string) {
+	var req pushSubscriptionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPushRequestBytes)).Decode(&req); err != nil {
+		http.Error(w, "不正なリクエストです", http.StatusBadRequest)
+		return
+	}
+	if err := push.ValidateEndpoint(req.Subscription.Endpoint); err != nil {
+		http.Error(w, "購読のエンドポイントが不正です", http.StatusBadRequest)
+		return
+	}
+	if req.Subscription.Keys.P256dh == "" || req.Subscription.Keys.Auth == "" {
+		http.Error(w, "購読の鍵が指定されていません", http.StatusBadRequest)
+		return
+	}
+
+	sub := model.PushSubscription{
+		DeviceID:      deviceID,
+		Endpoint:      req.Subscription.Endpoint,
+		P256dh:        req.Subscription.Keys.P256dh,
+		Auth:          req.Subscription.Keys.Auth,
+		MinLevel:      push.DefaultMinLevel,
+		JumpThreshold: push.DefaultJumpThreshold,
+		Spots:         []string{},
+	}
+	if req.MinLevel != nil {
+		if *req.MinLevel < 1 || *req.MinLevel > 5 {
+			http.Error(w, "min_levelは1〜5で指定してください", http.StatusBadRequest)
+			return
+		}
+		sub.MinLevel = *req.MinLevel
+	}
+	if req.JumpThreshold != nil {
+		if *req.JumpThreshold <= 0 || *req.JumpThreshold > 2 {
+			http.Error(w, "jump_thresholdは0より大きく2以下で指定してください", http.StatusBadRequest)
+			return
+		}
+		sub.JumpThreshold = *req.JumpThreshold
+	}
+	seen := map[string]bool{}
+	for _, id := range req.Spots {
+		if _, ok := spot.Get(id); !ok {
+			http.Error(w, "指定された地点は存在しません", http.StatusBadRequest)
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			sub.Spots = append(sub.Spots, id)
+		}
+	}
+
+	if err := h.push.Save(sub); err != nil {
+		h.log(r).Error("プッシュ通知の購読保存エラー", "error", err)
+		http.Error(w, "購読の登録に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	saved, err := h.push.Get(deviceID)
+	if err != nil || saved == nil {
+		h.log(r).Error("プッシュ通知の購読取得エラー", "error", err)
+		http.Error(w, "購読情報の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
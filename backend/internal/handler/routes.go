@@ -6,32 +6,101 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/accuracy"
 	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/config"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/history"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/push"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scheduler"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scoring"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/storage"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/webhook"
 )
 
 // Handler はハンドラ関数で共有する依存関係を保持
 type Handler struct {
-	db     *sql.DB
-	logger *slog.Logger
-	jwtKey []byte
-	cache  *cache.CacheManager
+	db        *sql.DB
+	logger    *slog.Logger
+	jwtKey    []byte
+	auth      config.Auth
+	cache     *cache.CacheManager
+	scheduler *scheduler.Scheduler
+	history   *history.Store
+	accuracy  *accuracy.Scorer
+	scoring   *scoring.Calculator
+	night     night.Config
+	season    level.Season
+	push      *push.Store
+	vapid     *push.Keys        // nilの場合はプッシュ通知を無効にする
+	siteURL   string            // フロントエンドのURL（カレンダーなどのリンクに使う）
+	storage   *storage.Supabase // nilの場合は画像付きの投稿を受け付けない
+
+	webhookStore *webhook.Store
+	webhooks     *webhook.Dispatcher
+
+	stop chan struct{} // Closeで閉じ、バックグラウンドの処理を止める
+	wg   sync.WaitGroup
 }
 
 // NewHandler は新しいHandlerを初期化
-func NewHandler(db *sql.DB, logger *slog.Logger, jwtKey []byte, cache *cache.CacheManager) *Handler {
-	return &Handler{
-		db:     db,
-		logger: logger,
-		jwtKey: jwtKey,
-		cache:  cache,
+// 秘密の値・リンク先・夜の時間帯はcfg（config.Loadで検証済みのもの）から取る
+func NewHandler(db *sql.DB, logger *slog.Logger, cfg *config.Config, cache *cache.CacheManager, scheduler *scheduler.Scheduler, history *history.Store, accuracy *accuracy.Scorer, scoring *scoring.Calculator, pushStore *push.Store, vapid *push.Keys, storage *storage.Supabase, webhookStore *webhook.Store, webhooks *webhook.Dispatcher) *Handler {
+	h := &Handler{
+		db:        db,
+		logger:    logger,
+		jwtKey:    []byte(cfg.Auth.JWTSecret),
+		auth:      cfg.Auth,
+		cache:     cache,
+		scheduler: scheduler,
+		history:   history,
+		accuracy:  accuracy,
+		scoring:   scoring,
+		night:     cfg.NightConfig(),
+		season:    cfg.Season(),
+		push:      pushStore,
+		vapid:     vapid,
+		siteURL:   cfg.Server.SiteURL,
+		storage:   storage,
+
+		webhookStore: webhookStore,
+		webhooks:     webhooks,
+
+		stop: make(chan struct{}),
 	}
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		runRateMapCleanup(h.stop)
+	}()
+	return h
+}
+
+// Close はレートマップの掃除などバックグラウンドの処理を止め、終わるまで待つ
+func (h *Handler) Close() {
+	close(h.stop)
+	h.wg.Wait()
 }
 
 // RegisterRoutes はサーバーの全ルートを登録
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", h.healthzHandler)
+	mux.HandleFunc("/readyz", h.readyzHandler)
 	mux.HandleFunc("/api/prediction", h.getPredictionHandler)
+	mux.HandleFunc("/api/prediction/history", h.getPredictionHistoryHandler)
+	mux.HandleFunc("/api/prediction/changes", h.getPredictionChangesHandler)
 	mux.HandleFunc("/api/detail/", h.getDetailHandler)
+	mux.HandleFunc("/api/spots", h.getSpotsHandler)
+	mux.HandleFunc("/api/index/", h.getIndexHandler)
+	mux.HandleFunc("/api/night/", h.getNightHandler)
+	mux.HandleFunc("/api/moon-calendar", h.getMoonCalendarHandler)
+	mux.HandleFunc("/api/calendar.ics", h.getCalendarHandler)
+	mux.HandleFunc("/api/feeds/", h.getFeedHandler)
+	mux.HandleFunc("/api/push/vapid-public-key", h.getVAPIDPublicKeyHandler)
+	mux.HandleFunc("/api/push/subscription", h.pushSubscriptionHandler)
 	mux.HandleFunc("/api/posts", h.postsHandler)
 	mux.HandleFunc("/api/posts/", h.postDetailHandler)
 	mux.HandleFunc("/api/replies/", h.replyDetailHandler)
@@ -42,7 +111,12 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/admin/banned-devices", h.authMiddleware(h.listBannedDevicesHandler))
 	mux.HandleFunc("/api/admin/ban", h.authMiddleware(h.banDeviceHandler))
 	mux.HandleFunc("/api/admin/ban/", h.authMiddleware(h.unbanDeviceHandler))
+	mux.HandleFunc("/api/admin/webhooks", h.authMiddleware(h.webhooksHandler))
+	mux.HandleFunc("/api/admin/webhooks/", h.authMiddleware(h.webhookDetailHandler))
+	mux.HandleFunc("/api/accuracy", h.getAccuracyHandler)
 	mux.HandleFunc("/api/tasks/refresh-cache", h.refreshCacheHandler)
+	mux.HandleFunc("/api/tasks/status", h.taskStatusHandler)
+	mux.HandleFunc("/api/tasks/score-accuracy", h.scoreAccuracyHandler)
 
 	// 起動時にBANリストをキャッシュに読み込む
 	if err := loadBannedDevices(h.db); err != nil {
@@ -0,0 +1,54 @@
+// backend/internal/handler/sightings.go
+package handler
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// insertSighting は投稿に添えた目撃報告を登録する（検証はsighting.Validateで済ませておく）
+func insertSighting(tx *sql.Tx, postID int, req *model.CreateSightingRequest) (*model.Sighting, error) {
+	s := model.Sighting{
+		PostID:     postID,
+		SpotID:     req.SpotID,
+		ObservedAt: req.ObservedAt,
+		Quantity:   req.Quantity,
+		Crowd:      req.Crowd,
+	}
+	query := `INSERT INTO sightings (post_id, spot_id, observed_at, quantity, crowd) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	if err := tx.QueryRow(query, postID, s.SpotID, s.ObservedAt, s.Quantity, s.Crowd).Scan(&s.ID, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// getSightingsForPosts は複数の投稿IDに対する目撃報告を一括取得する
+func (h *Handler) getSightingsForPosts(postIDs []int) (map[int]*model.Sighting, error) {
+	sightingsMap := make(map[int]*model.Sighting)
+	if len(postIDs) == 0 {
+		return sightingsMap, nil
+	}
+
+	query := `SELECT id, post_id, spot_id, observed_at, quantity, crowd, created_at
+		FROM sightings WHERE post_id = ANY($1)`
+	rows, err := h.db.Query(query, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.Sighting
+		var crowd sql.NullString
+		if err := rows.Scan(&s.ID, &s.PostID, &s.SpotID, &s.ObservedAt, &s.Quantity, &crowd, &s.CreatedAt); err != nil {
+			continue
+		}
+		if crowd.Valid {
+			s.Crowd = &crowd.String
+		}
+		sightingsMap[s.PostID] = &s
+	}
+	return sightingsMap, rows.Err()
+}
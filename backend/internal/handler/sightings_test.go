@@ -0,0 +1,168 @@
+// backend/internal/handler/sightings_test.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/webhook"
+)
+
+func newSightingTestHandler(t *testing.T) (*Handler, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := &Handler{
+		db:       db,
+		logger:   logger,
+		webhooks: webhook.NewDispatcher(webhook.NewStore(db), nil, "https://example.com", logger),
+	}
+	return h, mock
+}
+
+func sightingPostBody(label string) string {
+	body, _ := json.Marshal(model.Post{
+		Username: "テスト",
+		Content:  "岩瀬浜でちらほら",
+		Label:    label,
+		SightingRequest: &model.CreateSightingRequest{
+			SpotID:     spot.DefaultID,
+			ObservedAt: time.Now().Add(-time.Hour),
+			Quantity:   "ちらほら",
+		},
+	})
+	return string(body)
+}
+
+// TestCreatePostWithSighting は投稿と目撃報告を1つのトランザクションで登録することをテストする
+func TestCreatePostWithSighting(t *testing.T) {
+	h, mock := newSightingTestHandler(t)
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO posts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, now))
+	mock.ExpectQuery("INSERT INTO sightings").
+		WithArgs(10, spot.DefaultID, sqlmock.AnyArg(), "ちらほら", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, now))
+	mock.ExpectCommit()
+
+	rec := httptest.NewRecorder()
+	h.createPost(rec, httptest.NewRequest(http.MethodPost, "/api/posts", strings.NewReader(sightingPostBody("現地情報"))), true)
+	h.webhooks.Wait()
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var post model.Post
+	if err := json.Unmarshal(rec.Body.Bytes(), &post); err != nil {
+		t.Fatal(err)
+	}
+	if post.Sighting == nil || post.Sighting.ID != 3 || post.Sighting.PostID != 10 || post.SightingRequest != nil {
+		t.Errorf("sighting = %+v, request = %+v", post.Sighting, post.SightingRequest)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestCreatePostRejectsSightingOnOtherLabel は現地情報以外の投稿に添えた目撃報告をDBに触れずに拒否することをテストする
+func TestCreatePostRejectsSightingOnOtherLabel(t *testing.T) {
+	h, mock := newSightingTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.createPost(rec, httptest.NewRequest(http.MethodPost, "/api/posts", strings.NewReader(sightingPostBody("その他"))), true)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestGetPostsIncludesSightings は投稿一覧に目撃報告を含めることをテストする
+func TestGetPostsIncludesSightings(t *testing.T) {
+	h, mock := newSightingTestHandler(t)
+	now := time.Now()
+	mock.ExpectQuery("FROM posts p").WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "username", "content", "image_urls", "label", "created_at", "good_count", "bad_count", "device_id", "is_pinned"}).
+		AddRow(10, "テスト", "ちらほら", "{}", "現地情報", now, 0, 0, nil, false).
+		AddRow(11, "テスト", "雑談", "{}", "その他", now, 0, 0, nil, false))
+	mock.ExpectQuery("FROM polls").WillReturnError(errors.New("アンケートは対象外"))
+	mock.ExpectQuery("FROM sightings").WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "post_id", "spot_id", "observed_at", "quantity", "crowd", "created_at"}).
+		AddRow(3, 10, spot.DefaultID, now, "ちらほら", "混雑", now))
+
+	rec := httptest.NewRecorder()
+	h.getPosts(rec, httptest.NewRequest(http.MethodGet, "/api/posts", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var posts []model.Post
+	if err := json.Unmarshal(rec.Body.Bytes(), &posts); err != nil || len(posts) != 2 {
+		t.Fatalf("posts = %s (%v)", rec.Body, err)
+	}
+	if s := posts[0].Sighting; s == nil || s.Quantity != "ちらほら" || s.Crowd == nil || *s.Crowd != "混雑" {
+		t.Errorf("posts[0].Sighting = %+v", s)
+	}
+	if posts[1].Sighting != nil {
+		t.Errorf("posts[1].Sighting = %+v, want nil", posts[1].Sighting)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestUpdatePostLabelDeletesSighting は現地情報から他のラベルに変えた投稿の目撃報告を削除し、
+// 他のラベルから現地情報に変えた投稿を現地情報のWebhookに送ることをテストする
+func TestUpdatePostLabelDeletesSighting(t *testing.T) {
+	tests := []struct {
+		oldLabel, label string
+		wantsDeletion   bool
+		wantsWebhook    bool
+	}{
+		{"現地情報", "その他", true, false},
+		{"現地情報", "現地情報", false, false},
+		{"その他", "現地情報", false, true},
+	}
+	for _, tt := range tests {
+		h, mock := newSightingTestHandler(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE posts p SET label").WithArgs(tt.label, 10).WillReturnRows(sqlmock.NewRows(
+			[]string{"old_label", "id", "username", "content", "image_urls", "label", "created_at", "is_pinned"}).
+			AddRow(tt.oldLabel, 10, "テスト", "ちらほら", "{}", tt.label, time.Now(), false))
+		if tt.wantsDeletion {
+			mock.ExpectExec("DELETE FROM sightings").WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectCommit()
+		if tt.wantsWebhook {
+			mock.ExpectQuery("FROM webhooks").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		}
+
+		rec := httptest.NewRecorder()
+		h.updatePostLabel(rec, httptest.NewRequest(http.MethodPatch, "/api/posts/10/label", strings.NewReader(`{"label":"`+tt.label+`"}`)), 10)
+		h.webhooks.Wait()
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s→%s: status = %d, body = %s", tt.oldLabel, tt.label, rec.Code, rec.Body)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s→%s: %v", tt.oldLabel, tt.label, err)
+		}
+	}
+}
@@ -2,18 +2,20 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
-	"os"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
 )
 
 func (h *Handler) getPredictionHandler(w http.ResponseWriter, r *http.Request) {
-	data := h.cache.GetPredictionData()
-	if data == nil {
+	entry, ok := h.cache.GetPredictionEntry()
+	if !ok {
 		http.Error(w, "予測データはまだ利用できません。", http.StatusServiceUnavailable)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCacheEntry(w, r, entry)
 }
 
 func (h *Handler) getDetailHandler(w http.ResponseWriter, r *http.Request) {
@@ -22,25 +24,83 @@ func (h *Handler) getDetailHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "日付が指定されていません", http.StatusBadRequest)
 		return
 	}
+	if len(pathSegments) == 4 && pathSegments[3] == "timeline" {
+		h.getTimelineHandler(w, r, pathSegments[2])
+		return
+	}
+	if len(pathSegments) > 3 {
+		http.NotFound(w, r)
+		return
+	}
 	dateStr := pathSegments[2]
-	data, ok := h.cache.GetDetailData(dateStr)
+	spotID := r.URL.Query().Get("spot")
+	if spotID == "" {
+		spotID = spot.DefaultID
+	}
+	if _, ok := spot.Get(spotID); !ok {
+		http.Error(w, "指定された地点は存在しません", http.StatusBadRequest)
+		return
+	}
+	// schema=2 で型付きの形式（model.DetailData）を返す。未指定の場合は外部APIのレスポンスをまとめた従来の形式
+	var entry *cache.Entry
+	var ok bool
+	switch r.URL.Query().Get("schema") {
+	case "", "1":
+		entry, ok = h.cache.GetDetailEntry(spotID, dateStr)
+	case "2":
+		entry, ok = h.cache.GetDetailTypedEntry(spotID, dateStr)
+	default:
+		http.Error(w, "schemaは1または2を指定してください", http.StatusBadRequest)
+		return
+	}
 	if !ok {
 		http.Error(w, "指定された日付のデータは見つかりません", http.StatusNotFound)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCacheEntry(w, r, entry)
 }
 
-func (h *Handler) refreshCacheHandler(w http.ResponseWriter, r *http.Request) {
+// checkCronSecret はX-Cron-Secretヘッダーを検証する
+// 不一致の場合はエラーレスポンスを返してfalseを返す
+func (h *Handler) checkCronSecret(w http.ResponseWriter, r *http.Request) bool {
 	secretHeader := r.Header.Get("X-Cron-Secret")
-	expectedSecret := os.Getenv("CRON_SECRET_KEY")
+	expectedSecret := h.auth.CronSecret
 	if expectedSecret == "" || secretHeader != expectedSecret {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// 地点一覧を取得する (GET /api/spots)
+// 潮汐は富山湾全体で共通（富山港）なので、地点ごとの潮汐の観測点は返さない
+func (h *Handler) getSpotsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(spot.All())
+}
+
+func (h *Handler) refreshCacheHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.checkCronSecret(w, r) {
+		return
+	}
+	if !h.scheduler.Trigger("manual") {
+		http.Error(w, "Cache refresh already running.", http.StatusConflict)
 		return
 	}
-	go h.cache.FetchAndCachePredictionData()
-	go h.cache.FetchAndCacheDetailData()
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Cache refresh triggered."))
+}
+
+// キャッシュ更新ジョブの状態を取得する (GET /api/tasks/status)
+func (h *Handler) taskStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.scheduler.Status())
 }
\ No newline at end of file
@@ -0,0 +1,263 @@
+// backend/internal/handler/webhooks.go
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/webhook"
+)
+
+// 送信記録の取得件数の既定値と上限
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// index.aboveイベントの閾値の既定値
+const defaultWebhookIndexThreshold = 70
+
+// webhookRequest はWebhookの登録・更新のリクエスト
+// 更新時に省略した項目は変更しない
+type webhookRequest struct {
+	Name           *string  `json:"name"`
+	URL            *string  `json:"url"`
+	Format         *string  `json:"format"`
+	Events         []string `json:"events"`
+	AuthToken      *string  `json:"auth_token"` // 空文字で削除
+	IndexThreshold *int     `json:"index_threshold"`
+	SpotID         *string  `json:"spot_id"`
+	Enabled        *bool    `json:"enabled"`
+	RotateSecret   bool     `json:"rotate_secret"`
+}
+
+// Webhookの一覧取得・登録 (GET, POST /api/admin/webhooks)
+func (h *Handler) webhooksHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		hooks, err := h.webhookStore.List()
+		if err != nil {
+			h.log(r).Error("Webhook一覧の取得エラー", "error", err)
+			http.Error(w, "Webhook一覧の取得に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(hooks)
+	case http.MethodPost:
+		var req webhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "リクエストボディが不正です", http.StatusBadRequest)
+			return
+		}
+		hook := model.Webhook{
+			Format:         webhook.FormatJSON,
+			IndexThreshold: defaultWebhookIndexThreshold,
+			SpotID:         spot.DefaultID,
+			Enabled:        true,
+		}
+		if req.Name == nil || req.URL == nil || req.Events == nil {
+			http.Error(w, "name・url・eventsは必須です", http.StatusBadRequest)
+			return
+		}
+		if msg := applyWebhookRequest(&hook, req); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		hook.Secret = newWebhookSecret()
+		if err := h.webhookStore.Create(&hook); err != nil {
+			h.log(r).Error("Webhookの登録エラー", "error", err)
+			http.Error(w, "Webhookの登録に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(hook)
+	default:
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+	}
+}
+
+// Webhookの取得・更新・削除・送信記録・送信テスト
+//
+//	GET, PUT, DELETE  /api/admin/webhooks/{id}
+//	GET               /api/admin/webhooks/{id}/deliveries?limit=50
+//	POST              /api/admin/webhooks/{id}/test（再試行せずに1回だけ送る）
+func (h *Handler) webhookDetailHandler(w http.ResponseWriter, r *http.Request) {
+	pathSegments := splitPath(r.URL.Path)
+	if len(pathSegments) < 4 || len(pathSegments) > 5 {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := strconv.Atoi(pathSegments[3])
+	if err != nil {
+		http.Error(w, "不正なWebhook IDです", http.StatusBadRequest)
+		return
+	}
+	hook, err := h.webhookStore.Get(id)
+	if err != nil {
+		h.log(r).Error("Webhookの取得エラー", "error", err)
+		http.Error(w, "Webhookの取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if hook == nil {
+		http.Error(w, "Webhookが見つかりません", http.StatusNotFound)
+		return
+	}
+
+	if len(pathSegments) == 5 {
+		switch pathSegments[4] {
+		case "deliveries":
+			h.listWebhookDeliveries(w, r, hook.ID)
+		case "test":
+			if r.Method != http.MethodPost {
+				http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+				return
+			}
+			delivery := h.webhooks.SendOnce(*hook, webhook.TestEvent(h.siteURL))
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(delivery)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(hook)
+	case http.MethodPut:
+		var req webhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "リクエストボディが不正です", http.StatusBadRequest)
+			return
+		}
+		if msg := applyWebhookRequest(hook, req); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		if req.RotateSecret {
+			hook.Secret = newWebhookSecret()
+			if err := h.webhookStore.UpdateSecret(hook.ID, hook.Secret); err != nil {
+				h.log(r).Error("Webhookの署名鍵の更新エラー", "error", err)
+				http.Error(w, "Webhookの更新に失敗しました", http.StatusInternalServerError)
+				return
+			}
+		}
+		if _, err := h.webhookStore.Update(hook); err != nil {
+			h.log(r).Error("Webhookの更新エラー", "error", err)
+			http.Error(w, "Webhookの更新に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(hook)
+	case http.MethodDelete:
+		if _, err := h.webhookStore.Delete(hook.ID); err != nil {
+			h.log(r).Error("Webhookの削除エラー", "error", err)
+			http.Error(w, "Webhookの削除に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) listWebhookDeliveries(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "許可されていないメソッドです", http.StatusMethodNotAllowed)
+		return
+	}
+	limit := defaultDeliveryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxDeliveryLimit {
+			http.Error(w, "limitは1〜500で指定してください", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	deliveries, err := h.webhookStore.ListDeliveries(id, limit)
+	if err != nil {
+		h.log(r).Error("Webhook送信記録の取得エラー", "error", err)
+		http.Error(w, "送信記録の取得に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// applyWebhookRequest はリクエストの内容を検証してWebhookに反映する
+// 不正な項目がある場合はエラーメッセージを返す
+func applyWebhookRequest(hook *model.Webhook, req webhookRequest) string {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len([]rune(name)) > 50 {
+			return "nameは1〜50文字で指定してください"
+		}
+		hook.Name = name
+	}
+	if req.URL != nil {
+		u, err := url.Parse(strings.TrimSpace(*req.URL))
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return "urlはhttp(s)のURLで指定してください"
+		}
+		hook.URL = u.String()
+	}
+	if req.Format != nil {
+		if !slices.Contains(webhook.Formats, *req.Format) {
+			return "formatはjson・discord・slack・lineのいずれかを指定してください"
+		}
+		hook.Format = *req.Format
+	}
+	if req.Events != nil {
+		if len(req.Events) == 0 {
+			return "eventsを1つ以上指定してください"
+		}
+		events := []string{}
+		for _, e := range req.Events {
+			if !slices.Contains(webhook.Events, e) {
+				return "未対応のイベントです: " + e
+			}
+			if !slices.Contains(events, e) {
+				events = append(events, e)
+			}
+		}
+		hook.Events = events
+	}
+	if req.AuthToken != nil {
+		hook.AuthToken = strings.TrimSpace(*req.AuthToken)
+		hook.HasAuthToken = hook.AuthToken != ""
+	}
+	if req.IndexThreshold != nil {
+		if *req.IndexThreshold < 0 || *req.IndexThreshold > 100 {
+			return "index_thresholdは0〜100で指定してください"
+		}
+		hook.IndexThreshold = *req.IndexThreshold
+	}
+	if req.SpotID != nil {
+		if _, ok := spot.Get(*req.SpotID); !ok {
+			return "指定された地点は存在しません"
+		}
+		hook.SpotID = *req.SpotID
+	}
+	if req.Enabled != nil {
+		hook.Enabled = *req.Enabled
+	}
+	return ""
+}
+
+// newWebhookSecret は署名用の鍵（32バイトの乱数の16進数）を作る
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
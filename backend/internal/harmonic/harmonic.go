@@ -0,0 +1,222 @@
+// backend/internal/harmonic/harmonic.go
+package harmonic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// requiredConstituents は調和定数ファイルに必須の分潮
+// S2・N2がないと大潮・小潮の周期が、O1・P1・Q1がK1と別の遅角でないと日潮不等が再現できず、
+// 数日で満干の時刻がずれていくため、主要8分潮をすべて求める
+var requiredConstituents = []string{"M2", "S2", "N2", "K2", "K1", "O1", "P1", "Q1"}
+
+// Constituent は1分潮の調和定数
+type Constituent struct {
+	Name      string  `json:"name"`
+	Amplitude float64 `json:"amplitude"` // 振幅（cm）
+	Phase     float64 `json:"phase"`     // 世界時基準の遅角G（度）
+}
+
+// Station は潮位観測点と、その調和定数
+type Station struct {
+	Name         string        `json:"name"`
+	PrefCode     int           `json:"pref_code"`   // tide736.netの都道府県コード
+	HarborCode   int           `json:"harbor_code"` // tide736.netの港コード
+	Z0           float64       `json:"z0"`          // 潮位表基準面からの平均水面の高さ（cm）
+	Constituents []Constituent `json:"constituents"`
+}
+
+// Table は観測点ごとの調和定数の一覧
+type Table struct {
+	source   string
+	stations []Station
+}
+
+// Point は推算した潮位
+type Point struct {
+	Time   time.Time
+	Height float64 // 潮位（cm）
+}
+
+// Extremum は推算した満潮・干潮
+type Extremum struct {
+	Time   time.Time
+	Height float64 // 潮位（cm）
+	High   bool    // trueなら満潮
+}
+
+// LoadTable はJSONファイルから調和定数を読み込む
+// 推算値を潮汐データとして返すため、出典（気象庁の調和定数など）を"source"に書いていないファイルや
+// 主要8分潮（requiredConstituents）が揃っていないファイルは受け付けない
+func LoadTable(path string) (*Table, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("調和定数ファイルの読み込みに失敗しました: %w", err)
+	}
+	return parseTable(body)
+}
+
+func parseTable(body []byte) (*Table, error) {
+	var file struct {
+		Source   string    `json:"source"`
+		Stations []Station `json:"stations"`
+	}
+	if err := json.Unmarshal(body, &file); err != nil {
+		return nil, fmt.Errorf("調和定数の形式が不正です: %w", err)
+	}
+	if file.Source == "" {
+		return nil, fmt.Errorf("調和定数の出典（source）がありません")
+	}
+	for _, s := range file.Stations {
+		names := make(map[string]bool)
+		for _, c := range s.Constituents {
+			if _, ok := constituentArgs[c.Name]; !ok {
+				return nil, fmt.Errorf("%s: 未対応の分潮です: %s", s.Name, c.Name)
+			}
+			if names[c.Name] {
+				return nil, fmt.Errorf("%s: %sが重複しています", s.Name, c.Name)
+			}
+			if c.Amplitude < 0 {
+				return nil, fmt.Errorf("%s: %sの振幅が負の値です", s.Name, c.Name)
+			}
+			names[c.Name] = true
+		}
+		for _, name := range requiredConstituents {
+			if !names[name] {
+				return nil, fmt.Errorf("%s: %sの調和定数がありません（主要8分潮 %v がすべて必要です）", s.Name, name, requiredConstituents)
+			}
+		}
+	}
+	return &Table{source: file.Source, stations: file.Stations}, nil
+}
+
+// Source は調和定数の出典を返す
+func (t *Table) Source() string {
+	return t.source
+}
+
+// Station はtide736.netの都道府県コード・港コードに対応する観測点を返す
+func (t *Table) Station(prefCode, harborCode int) (*Station, bool) {
+	for i := range t.stations {
+		if t.stations[i].PrefCode == prefCode && t.stations[i].HarborCode == harborCode {
+			return &t.stations[i], true
+		}
+	}
+	return nil, false
+}
+
+// Height は指定時刻の潮位（cm）を推算する
+func (s *Station) Height(t time.Time) float64 {
+	a := astronomicalArgs(t)
+	h := s.Z0
+	for _, c := range s.Constituents {
+		f, u, v := constituentArgs[c.Name](a)
+		h += f * c.Amplitude * math.Cos(rad(v+u-c.Phase))
+	}
+	return h
+}
+
+// Curve はstartからend（endを含む）までstep間隔の潮位を推算する
+func (s *Station) Curve(start, end time.Time, step time.Duration) []Point {
+	var points []Point
+	for t := start; !t.After(end); t = t.Add(step) {
+		points = append(points, Point{Time: t, Height: s.Height(t)})
+	}
+	return points
+}
+
+// Extrema はstartからendまでの満潮・干潮を1分単位で求める
+func (s *Station) Extrema(start, end time.Time) []Extremum {
+	var result []Extremum
+	// 境界上の極値を判定するため前後1分ずつ余分に計算する
+	prev, cur := s.Height(start.Add(-time.Minute)), s.Height(start)
+	for t := start; t.Before(end); t = t.Add(time.Minute) {
+		next := s.Height(t.Add(time.Minute))
+		if cur > prev && cur >= next {
+			result = append(result, Extremum{Time: t, Height: cur, High: true})
+		} else if cur < prev && cur <= next {
+			result = append(result, Extremum{Time: t, Height: cur, High: false})
+		}
+		prev, cur = cur, next
+	}
+	return result
+}
+
+// args は天文引数（度）
+type args struct {
+	T float64 // グリニッジにおける平均太陽の時角 + 180度
+	s float64 // 月の平均黄経
+	h float64 // 太陽の平均黄経
+	p float64 // 月の近地点の平均黄経
+	N float64 // 月の昇交点の平均黄経
+}
+
+func astronomicalArgs(t time.Time) args {
+	utc := t.UTC()
+	jd := float64(utc.UnixMilli())/86400000 + 2440587.5
+	tc := (jd - 2451545.0) / 36525
+	hours := float64(utc.Hour()) + float64(utc.Minute())/60 + float64(utc.Second())/3600
+	return args{
+		T: 180 + 15*hours,
+		s: 218.3165 + 481267.8813*tc,
+		h: 280.4661 + 36000.7698*tc,
+		p: 83.3535 + 4069.0137*tc,
+		N: 125.0445 - 1934.1363*tc,
+	}
+}
+
+// constituentArgs は分潮ごとの交点因数f・交点補正角u・天文引数V（度）を返す（Schureman）
+var constituentArgs = map[string]func(a args) (f, u, v float64){
+	"M2": func(a args) (float64, float64, float64) {
+		f, u := m2Nodal(a.N)
+		return f, u, 2*a.T - 2*a.s + 2*a.h
+	},
+	"S2": func(a args) (float64, float64, float64) {
+		return 1, 0, 2 * a.T
+	},
+	"N2": func(a args) (float64, float64, float64) {
+		f, u := m2Nodal(a.N)
+		return f, u, 2*a.T - 3*a.s + 2*a.h + a.p
+	},
+	"K2": func(a args) (float64, float64, float64) {
+		n := rad(a.N)
+		f := 1.0241 + 0.2863*math.Cos(n) + 0.0083*math.Cos(2*n) - 0.0015*math.Cos(3*n)
+		u := -17.74*math.Sin(n) + 0.68*math.Sin(2*n) - 0.04*math.Sin(3*n)
+		return f, u, 2*a.T + 2*a.h
+	},
+	"K1": func(a args) (float64, float64, float64) {
+		n := rad(a.N)
+		f := 1.0060 + 0.1150*math.Cos(n) - 0.0088*math.Cos(2*n) + 0.0006*math.Cos(3*n)
+		u := -8.86*math.Sin(n) + 0.68*math.Sin(2*n) - 0.07*math.Sin(3*n)
+		return f, u, a.T + a.h + 90
+	},
+	"O1": func(a args) (float64, float64, float64) {
+		f, u := o1Nodal(a.N)
+		return f, u, a.T - 2*a.s + a.h - 90
+	},
+	"P1": func(a args) (float64, float64, float64) {
+		return 1, 0, a.T - a.h - 90
+	},
+	"Q1": func(a args) (float64, float64, float64) {
+		f, u := o1Nodal(a.N)
+		return f, u, a.T - 3*a.s + a.h + a.p - 90
+	},
+}
+
+func m2Nodal(node float64) (float64, float64) {
+	n := rad(node)
+	return 1.0004 - 0.0373*math.Cos(n) + 0.0002*math.Cos(2*n), -2.14 * math.Sin(n)
+}
+
+func o1Nodal(node float64) (float64, float64) {
+	n := rad(node)
+	f := 1.0089 + 0.1871*math.Cos(n) - 0.0147*math.Cos(2*n) + 0.0014*math.Cos(3*n)
+	u := 10.80*math.Sin(n) - 1.34*math.Sin(2*n) + 0.19*math.Sin(3*n)
+	return f, u
+}
+
+func rad(d float64) float64 { return d * math.Pi / 180 }
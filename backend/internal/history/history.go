@@ -0,0 +1,98 @@
+// backend/internal/history/history.go
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// Store は予測データのスナップショットをPostgresに保存・参照する
+type Store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// NewStore は新しいStoreを初期化する
+func NewStore(db *sql.DB, logger *slog.Logger) *Store {
+	return &Store{db: db, logger: logger}
+}
+
+// RecordPrediction は予測データの更新をスナップショットとして保存する
+// CacheManagerのPredictionListenerとして登録して使う
+func (s *Store) RecordPrediction(update cache.PredictionUpdate) {
+	if err := s.SaveSnapshot(update.FetchedAt, update.Hash, update.Data); err != nil {
+		s.logger.Error("予測スナップショットの保存に失敗しました", "error", err)
+		return
+	}
+	s.logger.Info("予測スナップショットを保存しました", "hash", update.Hash)
+}
+
+// SaveSnapshot は予測ペイロードを取得時刻とハッシュとともに保存する
+func (s *Store) SaveSnapshot(fetchedAt time.Time, hash string, payload []byte) error {
+	query := `INSERT INTO prediction_snapshots (fetched_at, content_hash, payload) VALUES ($1, $2, $3)`
+	if _, err := s.db.Exec(query, fetchedAt, hash, payload); err != nil {
+		return fmt.Errorf("スナップショット挿入失敗: %w", err)
+	}
+	return nil
+}
+
+// GetPredictionHistory は指定日の予測が更新ごとにどう変化したかを古い順に返す
+func (s *Store) GetPredictionHistory(date string, limit int) ([]model.PredictionHistoryEntry, error) {
+	targetDate, err := time.ParseInLocation("2006-01-02", date, jst)
+	if err != nil {
+		return nil, fmt.Errorf("日付の形式が不正です: %w", err)
+	}
+
+	// WHEREの評価順は保証されないため、配列でないpayloadはCASEで空の配列にしてから展開する
+	query := `SELECT fetched_at, content_hash, elem
+		FROM (
+			SELECT s.fetched_at, s.content_hash, elem
+			FROM prediction_snapshots s,
+				jsonb_array_elements(CASE WHEN jsonb_typeof(s.payload) = 'array' THEN s.payload ELSE '[]'::jsonb END) elem
+			WHERE elem->>'date' = $1
+			ORDER BY s.fetched_at DESC
+			LIMIT $2
+		) recent
+		ORDER BY fetched_at ASC`
+	rows, err := s.db.Query(query, date, limit)
+	if err != nil {
+		return nil, fmt.Errorf("予測履歴クエリ失敗: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []model.PredictionHistoryEntry{}
+	for rows.Next() {
+		var entry model.PredictionHistoryEntry
+		var forecast []byte
+		if err := rows.Scan(&entry.FetchedAt, &entry.ContentHash, &forecast); err != nil {
+			s.logger.Error("予測履歴行のスキャンエラー", "error", err)
+			continue
+		}
+		entry.Forecast = json.RawMessage(forecast)
+		entry.LeadDays = leadDays(entry.FetchedAt, targetDate)
+
+		var fields struct {
+			PredictedAmount *float64 `json:"predicted_amount"`
+		}
+		if err := json.Unmarshal(forecast, &fields); err == nil {
+			entry.PredictedAmount = fields.PredictedAmount
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// leadDays は取得時点（JST）から対象日まで何日前の予測だったかを返す
+func leadDays(fetchedAt, targetDate time.Time) int {
+	f := fetchedAt.In(jst)
+	fetchedDate := time.Date(f.Year(), f.Month(), f.Day(), 0, 0, 0, 0, jst)
+	return int(targetDate.Sub(fetchedDate).Hours() / 24)
+}
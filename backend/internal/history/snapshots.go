@@ -0,0 +1,42 @@
+// backend/internal/history/snapshots.go
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// Snapshot は保存済みの予測データ1件
+type Snapshot struct {
+	FetchedAt time.Time
+	Hash      string
+	Payload   []byte
+}
+
+// RecentSnapshots は指定時刻以降に保存された予測データのうち、直前と内容が異なるものを古い順に返す
+func (s *Store) RecentSnapshots(since time.Time) ([]Snapshot, error) {
+	query := `SELECT fetched_at, content_hash, payload
+		FROM (
+			SELECT fetched_at, content_hash, payload,
+				LAG(content_hash) OVER (ORDER BY fetched_at) AS prev_hash
+			FROM prediction_snapshots
+			WHERE fetched_at >= $1
+		) s
+		WHERE prev_hash IS DISTINCT FROM content_hash
+		ORDER BY fetched_at ASC`
+	rows, err := s.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("スナップショット一覧クエリ失敗: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []Snapshot{}
+	for rows.Next() {
+		var snap Snapshot
+		if err := rows.Scan(&snap.FetchedAt, &snap.Hash, &snap.Payload); err != nil {
+			return nil, fmt.Errorf("スナップショット行のスキャン失敗: %w", err)
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,110 @@
+// backend/internal/ical/ical.go
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 1行の最大オクテット数（これを超える行は折り返す）
+const maxLineOctets = 75
+
+// Calendar はiCalendar（RFC 5545）のカレンダー
+type Calendar struct {
+	ProductID string // 例: -//bakuwaki-yoho//forecast//JA
+	Name      string // カレンダーアプリに表示される名前（X-WR-CALNAME）
+	Refresh   time.Duration
+	Events    []Event
+}
+
+// Event は1件の予定
+// UIDとSequenceを保ったまま内容を変えると、購読しているカレンダーアプリ側の予定が更新される
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time // 予定の内容を作成した時刻
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+}
+
+// Write はカレンダーをiCalendar形式で書き出す
+func (c *Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", c.ProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	line("X-WR-TIMEZONE", "Asia/Tokyo")
+	if c.Refresh > 0 {
+		minutes := int(c.Refresh.Minutes())
+		line("REFRESH-INTERVAL;VALUE=DURATION", fmt.Sprintf("PT%dM", minutes))
+		line("X-PUBLISHED-TTL", fmt.Sprintf("PT%dM", minutes))
+	}
+	for _, e := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("SEQUENCE", fmt.Sprint(e.Sequence))
+		line("DTSTAMP", formatTime(e.Stamp))
+		line("LAST-MODIFIED", formatTime(e.Stamp))
+		line("DTSTART", formatTime(e.Start))
+		line("DTEND", formatTime(e.End))
+		line("SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escapeText(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escapeText(e.Location))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// formatTime はUTCの日時形式（例: 20260412T130000Z）にする
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText はTEXT型の値に含まれる特殊文字をエスケープする
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// writeFolded は75オクテットを超える行をUTF-8の文字の途中で切らないように折り返して書き出す
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// 継続行は先頭の空白1文字分短くする
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,60 @@
+// backend/internal/ical/ical_test.go
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	start := time.Date(2026, 4, 12, 22, 0, 0, 0, jst)
+	cal := Calendar{
+		ProductID: "-//test//JA",
+		Name:      "爆湧き予報",
+		Refresh:   time.Hour,
+		Events: []Event{{
+			UID:         "2026-04-12-iwasehama@example.com",
+			Sequence:    3,
+			Stamp:       start.Add(-10 * time.Hour),
+			Start:       start,
+			End:         start.Add(4 * time.Hour),
+			Summary:     "大湧き予報, 予測値1.32; 岩瀬浜",
+			Description: "おすすめは22:00〜02:00\n" + strings.Repeat("月齢3.2（三日月）", 10),
+			URL:         "https://example.com/detail/2026-04-12",
+		}},
+	}
+	var buf bytes.Buffer
+	if err := cal.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT60M\r\n",
+		"UID:2026-04-12-iwasehama@example.com\r\n",
+		"SEQUENCE:3\r\n",
+		"DTSTART:20260412T130000Z\r\n",
+		"DTEND:20260412T170000Z\r\n",
+		`SUMMARY:大湧き予報\, 予測値1.32\; 岩瀬浜` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("出力に %q が含まれていません\n%s", want, out)
+		}
+	}
+
+	// 折り返した行を戻すと元の値になり、どの行も75オクテット以内
+	for _, l := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(l) > maxLineOctets {
+			t.Errorf("行が長すぎます（%dオクテット）: %q", len(l), l)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, `DESCRIPTION:おすすめは22:00〜02:00\n`+strings.Repeat("月齢3.2（三日月）", 10)+"\r\n") {
+		t.Errorf("DESCRIPTIONが正しく折り返されていません\n%s", out)
+	}
+}
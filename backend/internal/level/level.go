@@ -0,0 +1,121 @@
+// backend/internal/level/level.go
+package level
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 予測値からレベルを判定する閾値 [爆湧き, 大湧き, 湧き, チョイ湧き, プチ湧き]
+// frontend/lib/utils.ts の WAKI_THRESHOLDS と揃える
+var thresholds = []float64{1.4, 1.15, 0.9, 0.65, 0.4}
+
+var names = []string{"湧きなし", "プチ湧き", "チョイ湧き", "湧き", "大湧き", "爆湧き"}
+
+// FromAmount は予測値から0（湧きなし）〜5（爆湧き）のレベルを判定する
+func FromAmount(amount float64) int {
+	for i, t := range thresholds {
+		if amount >= t {
+			return len(thresholds) - i
+		}
+	}
+	return 0
+}
+
+// Threshold はそのレベルになる予測値の下限を返す（0以下は0）
+func Threshold(level int) float64 {
+	if level <= 0 {
+		return 0
+	}
+	if level > len(thresholds) {
+		level = len(thresholds)
+	}
+	return thresholds[len(thresholds)-level]
+}
+
+// Name はレベルの名前を返す
+func Name(level int) string {
+	if level < 0 || level >= len(names) {
+		return "シーズン外"
+	}
+	return names[level]
+}
+
+var weekdays = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// FormatDate は「2006-01-02」形式の日付を通知やフィードの見出し用に「4/10(金)」の形式で返す
+// 解釈できない場合はそのまま返す
+func FormatDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return fmt.Sprintf("%d/%d(%s)", t.Month(), t.Day(), weekdays[t.Weekday()])
+}
+
+// Season は開始月の1日〜終了月の末日の期間（年をまたいでもよい）
+// 予報のシーズン（FORECAST_SEASON_MONTHS）とキャッシュの更新間隔を短くする期間（REFRESH_SEASON_MONTHS）に使う
+type Season struct {
+	StartMonth time.Month
+	EndMonth   time.Month
+}
+
+// DefaultSeason は既定の予報のシーズン（2月〜5月。フロントエンドのシーズン判定と揃える）
+func DefaultSeason() Season {
+	return Season{StartMonth: time.February, EndMonth: time.May}
+}
+
+// ParseSeason は「開始月-終了月」（例: 3-5）の形式のシーズンを読み込む
+func ParseSeason(v string) (Season, error) {
+	start, end, ok := parseMonthRange(v)
+	if !ok {
+		return Season{}, fmt.Errorf("シーズンの月の形式が不正です（例: 3-5）: %q", v)
+	}
+	return Season{StartMonth: start, EndMonth: end}, nil
+}
+
+func parseMonthRange(v string) (time.Month, time.Month, bool) {
+	parts := strings.SplitN(v, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	start, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	end, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || start < 1 || start > 12 || end < 1 || end > 12 {
+		return 0, 0, false
+	}
+	return time.Month(start), time.Month(end), true
+}
+
+// String は「開始月-終了月」の形式で返す
+func (s Season) String() string {
+	return fmt.Sprintf("%d-%d", s.StartMonth, s.EndMonth)
+}
+
+// Contains は指定日がシーズン中かどうかを返す
+func (s Season) Contains(date time.Time) bool {
+	m := date.Month()
+	if s.StartMonth <= s.EndMonth {
+		return m >= s.StartMonth && m <= s.EndMonth
+	}
+	return m >= s.StartMonth || m <= s.EndMonth
+}
+
+// Range は指定日を含むシーズン（シーズン外の場合は次のシーズン）の初日と最終日を返す
+func (s Season) Range(date time.Time) (time.Time, time.Time) {
+	loc := date.Location()
+	year := date.Year()
+	switch {
+	case s.StartMonth > s.EndMonth && date.Month() <= s.EndMonth:
+		// 年をまたぐシーズンの後半
+		year--
+	case s.StartMonth <= s.EndMonth && date.Month() > s.EndMonth:
+		year++
+	}
+	start := time.Date(year, s.StartMonth, 1, 0, 0, 0, 0, loc)
+	months := int(s.EndMonth-s.StartMonth+12)%12 + 1
+	end := start.AddDate(0, months, -1)
+	return start, end
+}
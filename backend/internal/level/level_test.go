@@ -0,0 +1,83 @@
+// backend/internal/level/level_test.go
+package level
+
+import (
+	"testing"
+	"time"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// TestFormatDate は見出し用の日付の形式をテストする
+func TestFormatDate(t *testing.T) {
+	cases := map[string]string{
+		"2026-04-10": "4/10(金)",
+		"2026-03-01": "3/1(日)",
+		"不正な日付":      "不正な日付",
+	}
+	for date, want := range cases {
+		if got := FormatDate(date); got != want {
+			t.Errorf("FormatDate(%q) = %q, want %q", date, got, want)
+		}
+	}
+}
+
+// TestParseSeason は月の範囲指定の解析をテストする
+func TestParseSeason(t *testing.T) {
+	if s, err := ParseSeason("2-5"); err != nil || s != DefaultSeason() || s.String() != "2-5" {
+		t.Errorf("ParseSeason(\"2-5\") = (%v, %v)", s, err)
+	}
+	for _, v := range []string{"3", "0-5", "3-13", "a-b"} {
+		if _, err := ParseSeason(v); err == nil {
+			t.Errorf("ParseSeason(%q) は失敗するべきです", v)
+		}
+	}
+}
+
+// TestSeasonContains はシーズン中の判定が年をまたぐシーズンにも対応することをテストする
+func TestSeasonContains(t *testing.T) {
+	spring := DefaultSeason()
+	winter := Season{StartMonth: time.November, EndMonth: time.February}
+	cases := []struct {
+		month          time.Month
+		spring, winter bool
+	}{
+		{time.January, false, true},
+		{time.February, true, true},
+		{time.March, true, false},
+		{time.May, true, false},
+		{time.June, false, false},
+		{time.November, false, true},
+	}
+	for _, c := range cases {
+		date := time.Date(2026, c.month, 15, 0, 0, 0, 0, jst)
+		if got := spring.Contains(date); got != c.spring {
+			t.Errorf("2-5の%s: Contains = %v", c.month, got)
+		}
+		if got := winter.Contains(date); got != c.winter {
+			t.Errorf("11-2の%s: Contains = %v", c.month, got)
+		}
+	}
+}
+
+// TestSeasonRange は指定日を含むシーズン、シーズン外の場合は次のシーズンの期間を返すことをテストする
+func TestSeasonRange(t *testing.T) {
+	cases := []struct {
+		season     Season
+		date       string
+		start, end string
+	}{
+		{DefaultSeason(), "2026-04-10", "2026-02-01", "2026-05-31"},
+		{DefaultSeason(), "2026-01-10", "2026-02-01", "2026-05-31"},
+		{DefaultSeason(), "2026-06-01", "2027-02-01", "2027-05-31"},
+		{Season{StartMonth: time.November, EndMonth: time.February}, "2026-01-10", "2025-11-01", "2026-02-28"},
+		{Season{StartMonth: time.November, EndMonth: time.February}, "2026-12-10", "2026-11-01", "2027-02-28"},
+	}
+	for _, c := range cases {
+		date, _ := time.ParseInLocation("2006-01-02", c.date, jst)
+		start, end := c.season.Range(date)
+		if start.Format("2006-01-02") != c.start || end.Format("2006-01-02") != c.end {
+			t.Errorf("%vの%s: Range = %s〜%s, want %s〜%s", c.season, c.date, start.Format("2006-01-02"), end.Format("2006-01-02"), c.start, c.end)
+		}
+	}
+}
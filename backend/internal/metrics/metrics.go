@@ -0,0 +1,92 @@
+// backend/internal/metrics/metrics.go
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bakuwaki"
+
+var (
+	// HTTPRequestDuration はルート・メソッド・ステータスごとのレスポンス時間
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTPリクエストの処理時間（ルート・メソッド・ステータス別）",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"route", "method", "status"})
+
+	// UpstreamFetchDuration は外部API（データ取得元）ごとの取得時間
+	UpstreamFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_fetch_duration_seconds",
+		Help:      "データ取得元ごとの取得時間",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"source"})
+
+	// UpstreamFetchFailures は外部API（データ取得元）ごとの取得失敗回数
+	UpstreamFetchFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_fetch_failures_total",
+		Help:      "データ取得元ごとの取得失敗回数",
+	}, []string{"source"})
+
+	// RateLimitRejections はレート制限で拒否したリクエスト数
+	// kindは post / react、scopeは global / ip / device
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "レート制限で拒否したリクエスト数",
+	}, []string{"kind", "scope"})
+
+	// BanHits はBANされたデバイスからの書き込みを拒否した回数
+	BanHits = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ban_hits_total",
+		Help:      "BANされたデバイスからの書き込みを拒否した回数",
+	})
+
+	// ContentCreated は作成された投稿・返信・リアクション・投票の数
+	ContentCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "content_created_total",
+		Help:      "作成された投稿・返信・リアクション・投票の数",
+	}, []string{"type"})
+)
+
+// ContentCreatedのtypeラベルの値
+const (
+	ContentPost     = "post"
+	ContentReply    = "reply"
+	ContentReaction = "reaction"
+	ContentVote     = "vote"
+)
+
+// RegisterDB はデータベースの接続プールの統計を登録する
+func RegisterDB(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// Handler は/metricsのハンドラを返す
+// 「Authorization: Bearer <token>」のリクエストだけに応答する（tokenが空の場合は公開しない）
+func Handler(token string) http.Handler {
+	if token == "" {
+		return http.NotFoundHandler()
+	}
+	h := promhttp.Handler()
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "認証されていません", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,102 @@
+// backend/internal/metrics/metrics_test.go
+package metrics
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+func TestMiddlewareLabelsByPattern(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/detail/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "見つかりません", http.StatusNotFound)
+	})
+	handler := Middleware(mux)
+
+	for _, path := range []string{"/api/detail/2026-04-01", "/api/detail/2026-04-02", "/nothing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if n := testutil.CollectAndCount(HTTPRequestDuration); n != 2 {
+		t.Errorf("系列の数 = %d, want 2（日付ごとに増えないこと）", n)
+	}
+	if !HTTPRequestDuration.DeleteLabelValues("/api/detail/", "GET", "404") {
+		t.Error("ルートのパターンでラベルが付いていません")
+	}
+	if !HTTPRequestDuration.DeleteLabelValues("unmatched", "GET", "404") {
+		t.Error("muxに登録されていないパスがunmatchedになっていません")
+	}
+}
+
+type failingTide struct{}
+
+func (failingTide) Name() string { return "tide736" }
+
+func (failingTide) FetchTide(spot.Spot, time.Time) (map[string]interface{}, error) {
+	return nil, errors.New("timeout")
+}
+
+func TestInstrumentProvidersCountsFailures(t *testing.T) {
+	set := InstrumentProviders(provider.Set{Tide: failingTide{}})
+	before := testutil.ToFloat64(UpstreamFetchFailures.WithLabelValues("tide736"))
+	if _, err := set.Tide.FetchTide(spot.Default(), time.Now()); err == nil {
+		t.Fatal("エラーがそのまま返るべき")
+	}
+	if set.Tide.Name() != "tide736" {
+		t.Errorf("Name = %q", set.Tide.Name())
+	}
+	if got := testutil.ToFloat64(UpstreamFetchFailures.WithLabelValues("tide736")); got != before+1 {
+		t.Errorf("failures = %v, want %v", got, before+1)
+	}
+}
+
+// TestHandlerRequiresToken はトークンが未設定の場合は公開せず、設定した場合はBearerトークンを求めることをテストする
+func TestHandlerRequiresToken(t *testing.T) {
+	tests := []struct {
+		token, auth string
+		want        int
+	}{
+		{"", "", http.StatusNotFound},
+		{"secret", "", http.StatusUnauthorized},
+		{"secret", "Bearer wrong", http.StatusUnauthorized},
+		{"secret", "Bearer secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		Handler(tt.token).ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Handler(%q) with %q = %d, want %d", tt.token, tt.auth, rec.Code, tt.want)
+		}
+	}
+}
+
+// TestCacheCollectorOmitsDate は詳細データの経過時間を日付ごとではなく地点ごとに出力し、
+// 今日から6日後までに含まれない古い日付の詳細データを出力しないことをテストする
+func TestCacheCollectorOmitsDate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cm := cache.NewCacheManagerWithOptions(logger, provider.NewFixtureSet("../../testdata/fixtures"), cache.Options{})
+	cm.FetchAndCacheDetailData()
+
+	collector := newCacheCollector(cm)
+	if n := testutil.CollectAndCount(collector); n != len(spot.All()) {
+		t.Errorf("系列の数 = %d, want %d（地点ごとに1つ）", n, len(spot.All()))
+	}
+	collector.now = func() time.Time { return time.Now().AddDate(0, 0, 30) }
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Errorf("系列の数 = %d, want 0（過去の日付の詳細データは出力しない）", n)
+	}
+}
@@ -0,0 +1,111 @@
+// backend/internal/metrics/sources.go
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// InstrumentProviders はデータ取得元ごとの取得時間と失敗回数を記録するようにする
+// sourceラベルには各取得元のName()（open_meteo, tide736, prediction_apiなど）を使う
+func InstrumentProviders(set provider.Set) provider.Set {
+	set.Prediction = instrumentedPrediction{set.Prediction}
+	set.Weather = instrumentedWeather{set.Weather}
+	set.Tide = instrumentedTide{set.Tide}
+	if set.TideFallback != nil {
+		set.TideFallback = instrumentedTide{set.TideFallback}
+	}
+	return set
+}
+
+func observeFetch(source string, start time.Time, err error) {
+	UpstreamFetchDuration.WithLabelValues(source).Observe(time.Since(start).Seconds())
+	if err != nil {
+		UpstreamFetchFailures.WithLabelValues(source).Inc()
+	}
+}
+
+type instrumentedPrediction struct{ provider.PredictionProvider }
+
+func (p instrumentedPrediction) FetchPrediction() ([]byte, error) {
+	start := time.Now()
+	data, err := p.PredictionProvider.FetchPrediction()
+	observeFetch(p.Name(), start, err)
+	return data, err
+}
+
+type instrumentedWeather struct{ provider.WeatherProvider }
+
+func (p instrumentedWeather) FetchWeather(s spot.Spot, date time.Time) (map[string]interface{}, error) {
+	start := time.Now()
+	data, err := p.WeatherProvider.FetchWeather(s, date)
+	observeFetch(p.Name(), start, err)
+	return data, err
+}
+
+type instrumentedTide struct{ provider.TideProvider }
+
+func (p instrumentedTide) FetchTide(s spot.Spot, date time.Time) (map[string]interface{}, error) {
+	start := time.Now()
+	data, err := p.TideProvider.FetchTide(s, date)
+	observeFetch(p.Name(), start, err)
+	return data, err
+}
+
+// 経過時間を出力する詳細データの期間（今日から何日先まで）
+const cacheDetailDays = 7
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// cacheCollector はスクレイプ時にキャッシュされているデータの経過時間を集める
+type cacheCollector struct {
+	cache *cache.CacheManager
+	now   func() time.Time
+	age   *prometheus.Desc
+}
+
+// RegisterCache はキャッシュの経過時間を登録する
+// 予測データはkind="prediction"、詳細データはkind="detail"で地点ごとに今日から6日後までのうち最も古いものを出力する
+// 詳細データのキャッシュは過去の日付も残るので、日付をラベルにすると系列が日ごとに増え続ける
+func RegisterCache(c *cache.CacheManager) {
+	prometheus.MustRegister(newCacheCollector(c))
+}
+
+func newCacheCollector(c *cache.CacheManager) *cacheCollector {
+	return &cacheCollector{
+		cache: c,
+		now:   time.Now,
+		age: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "age_seconds"),
+			"キャッシュされているデータを取得してからの経過時間",
+			[]string{"kind", "spot"}, nil),
+	}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.age
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+	if entry, ok := c.cache.GetPredictionEntry(); ok {
+		ch <- prometheus.MustNewConstMetric(c.age, prometheus.GaugeValue, now.Sub(entry.FetchedAt).Seconds(),
+			"prediction", "")
+	}
+	for _, s := range spot.All() {
+		var oldest time.Time
+		for i := 0; i < cacheDetailDays; i++ {
+			date := now.In(jst).AddDate(0, 0, i).Format("2006-01-02")
+			if entry, ok := c.cache.GetDetailEntry(s.ID, date); ok && (oldest.IsZero() || entry.FetchedAt.Before(oldest)) {
+				oldest = entry.FetchedAt
+			}
+		}
+		if !oldest.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.age, prometheus.GaugeValue, now.Sub(oldest).Seconds(),
+				"detail", s.ID)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// backend/internal/model/detail.go
+package model
+
+import "time"
+
+// DetailSchemaVersionは /api/detail/{date}?schema=2 で返す詳細データの形式のバージョン
+// フィールドの削除や意味の変更を行う場合はバージョンを上げる（追加のみの場合は上げない）
+const DetailSchemaVersion = 2
+
+// DetailDataは1地点・1日分の詳細データ（schema=2）
+//
+// 時刻はすべてJST（+09:00）のRFC3339形式で返す
+// weatherは対象日0時から翌日23時までの48時間分
+type DetailData struct {
+	SchemaVersion int             `json:"schema_version"`
+	Date          string          `json:"date"`
+	Spot          DetailSpot      `json:"spot"`
+	FetchedAt     time.Time       `json:"fetched_at"`
+	Weather       []HourlyWeather `json:"weather"`
+	Tide          TideDay         `json:"tide"`
+	NextTide      *TideDay        `json:"next_tide"` // 翌日の潮汐（取得できなかった場合はnull）
+}
+
+// DetailSpotは詳細データの対象地点
+type DetailSpot struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// HourlyWeatherは1時間ごとの気象データ
+type HourlyWeather struct {
+	Time                     time.Time `json:"time"`
+	Temperature              float64   `json:"temperature"`               // 気温（℃）
+	Precipitation            float64   `json:"precipitation"`             // 降水量（mm）
+	PrecipitationProbability float64   `json:"precipitation_probability"` // 降水確率（%）
+	WeatherCode              int       `json:"weather_code"`              // WMO天気コード
+	WindSpeed                float64   `json:"wind_speed"`                // 風速（m/s）
+	WindDirection            float64   `json:"wind_direction"`            // 風向（度、北=0で時計回り、風が吹いてくる方向）
+}
+
+// TideDayは1日分の潮汐データ
+type TideDay struct {
+	Date     string      `json:"date"`
+	Source   string      `json:"source"`    // データの取得元（"tide736"）
+	TideName string      `json:"tide_name"` // 潮名（大潮・中潮など）
+	Moon     MoonInfo    `json:"moon"`
+	Sun      SunInfo     `json:"sun"`
+	Events   []TideEvent `json:"events"` // 満潮・干潮を時刻順に並べたもの
+	Curve    []TidePoint `json:"curve"`  // 潮位の推移
+}
+
+// MoonInfoは月の情報
+// 月齢・輝面比は潮汐データの値を使い、ない場合と月相・月の出入りは地点の座標から計算する
+type MoonInfo struct {
+	Age          *float64   `json:"age"`          // 月齢
+	Illumination *float64   `json:"illumination"` // 輝面比（%）
+	Phase        string     `json:"phase"`        // 月相（新月、上弦の月など）
+	Rise         *time.Time `json:"rise"`         // 月の出（その日に月の出がない場合はnull）
+	Set          *time.Time `json:"set"`          // 月の入り（その日に月の入りがない場合はnull）
+}
+
+// SunInfoは日の出・日の入り（潮汐データにない場合は地点の座標から計算する）
+type SunInfo struct {
+	Rise *time.Time `json:"rise"`
+	Set  *time.Time `json:"set"`
+}
+
+// 潮汐イベントの種類
+const (
+	TideFlood = "flood" // 満潮
+	TideEbb   = "ebb"   // 干潮
+)
+
+// TideEventは満潮・干潮
+type TideEvent struct {
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	Height float64   `json:"height"` // 潮位（cm）
+}
+
+// TidePointは潮位曲線の1点
+type TidePoint struct {
+	Time   time.Time `json:"time"`
+	Height float64   `json:"height"` // 潮位（cm）
+}
+
+// AstronomyDayは地点の座標から計算した1日分の月と太陽の情報
+type AstronomyDay struct {
+	Date             string     `json:"date"`
+	MoonAge          float64    `json:"moon_age"`          // 正午（JST）時点の月齢
+	MoonPhase        string     `json:"moon_phase"`        // 月相
+	MoonIllumination float64    `json:"moon_illumination"` // 正午（JST）時点の輝面比（%）
+	Moonrise         *time.Time `json:"moonrise"`
+	Moonset          *time.Time `json:"moonset"`
+	Sunrise          *time.Time `json:"sunrise"`
+	Sunset           *time.Time `json:"sunset"`
+}
+
+// MoonCalendarResponseは月齢カレンダーAPIのレスポンス
+type MoonCalendarResponse struct {
+	SpotID string         `json:"spot_id"`
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Days   []AstronomyDay `json:"days"`
+}
+
+// NightSummaryは1夜分（既定では20時〜翌5時 JST）の気象・潮汐・暗さのまとめ
+type NightSummary struct {
+	Date                        string      `json:"date"` // 夜が始まる日
+	SpotID                      string      `json:"spot_id"`
+	Start                       time.Time   `json:"start"`
+	End                         time.Time   `json:"end"`
+	TemperatureMin              float64     `json:"temperature_min"`
+	TemperatureMax              float64     `json:"temperature_max"`
+	TemperatureAvg              float64     `json:"temperature_avg"`
+	PrecipitationTotal          float64     `json:"precipitation_total"`           // 降水量の合計（mm）
+	PrecipitationProbabilityMax float64     `json:"precipitation_probability_max"` // 最大降水確率（%）
+	WindSpeedAvg                float64     `json:"wind_speed_avg"`
+	WindSpeedMax                float64     `json:"wind_speed_max"`
+	WindDirection               float64     `json:"wind_direction"` // 平均風向（度、風速で重み付けしたベクトル平均）
+	TideSource                  string      `json:"tide_source"`
+	LowestTide                  *TidePoint  `json:"lowest_tide"`
+	HighestTide                 *TidePoint  `json:"highest_tide"`
+	TideEvents                  []TideEvent `json:"tide_events"` // 夜の間の満潮・干潮
+	Moon                        MoonInfo    `json:"moon"`
+	DarkPeriods                 []TimeRange `json:"dark_periods"` // 太陽が天文薄明より下にあり、月が沈んでいる時間帯
+	DarkMinutes                 int         `json:"dark_minutes"`
+	Hours                       []NightHour `json:"hours"`
+}
+
+// TimeRangeは時間帯
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// NightHourは夜の1時間分の条件
+type NightHour struct {
+	Time                     time.Time `json:"time"`
+	Temperature              float64   `json:"temperature"`
+	Precipitation            float64   `json:"precipitation"`
+	PrecipitationProbability float64   `json:"precipitation_probability"`
+	WeatherCode              int       `json:"weather_code"`
+	WindSpeed                float64   `json:"wind_speed"`
+	WindDirection            float64   `json:"wind_direction"`
+	TideHeight               *float64  `json:"tide_height"`   // 正時の潮位（cm）
+	SunAltitude              float64   `json:"sun_altitude"`  // 30分時点の太陽高度（度）
+	MoonAltitude             float64   `json:"moon_altitude"` // 30分時点の月高度（度）
+	Dark                     bool      `json:"dark"`
+}
+
+// 時間ごとの判定
+const (
+	VerdictGo    = "go"    // 行き時
+	VerdictMaybe = "maybe" // 条件次第
+	VerdictNoGo  = "no_go" // 見送り
+)
+
+// Timelineは夜の時間ごとの掬いやすさ
+type Timeline struct {
+	Date       string         `json:"date"`
+	SpotID     string         `json:"spot_id"`
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	BestWindow *TimeRange     `json:"best_window"` // 判定がgoの時間が最も長く続く時間帯（ない場合はnull）
+	Summary    string         `json:"summary"`     // 「おすすめは23:00〜02:00」のような一文
+	Hours      []TimelineHour `json:"hours"`
+}
+
+// TimelineHourは1時間分の評価と、その理由
+type TimelineHour struct {
+	Time    time.Time       `json:"time"`
+	Score   int             `json:"score"`   // 0〜100
+	Verdict string          `json:"verdict"` // go, maybe, no_go
+	Factors TimelineFactors `json:"factors"`
+	Reasons []string        `json:"reasons"`
+}
+
+// TimelineFactorsは要因ごとの評価（0〜1）
+type TimelineFactors struct {
+	Tide     float64 `json:"tide"`
+	Wind     float64 `json:"wind"`
+	Rain     float64 `json:"rain"`
+	Darkness float64 `json:"darkness"`
+}
@@ -2,6 +2,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,6 +23,9 @@ type Post struct {
 	IsPinned    bool               `json:"is_pinned"`
 	Poll        *Poll              `json:"poll,omitempty"`
 	PollRequest *CreatePollRequest `json:"poll_request,omitempty"`
+	Sighting    *Sighting          `json:"sighting,omitempty"`
+	// SightingRequestは投稿と一緒に登録する目撃報告（レスポンスには含めない）
+	SightingRequest *CreateSightingRequest `json:"sighting_request,omitempty"`
 }
 
 // Pollはアンケート
@@ -43,6 +47,25 @@ type PollOption struct {
 	DisplayOrder int    `json:"display_order"`
 }
 
+// Sightingは現地情報の投稿に添えた目撃報告
+type Sighting struct {
+	ID         int       `json:"id"`
+	PostID     int       `json:"post_id"`
+	SpotID     string    `json:"spot_id"`
+	ObservedAt time.Time `json:"observed_at"`
+	Quantity   string    `json:"quantity"`        // なし/ちらほら/そこそこ/爆湧き
+	Crowd      *string   `json:"crowd,omitempty"` // 空いている/普通/混雑
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateSightingRequestは目撃報告の登録リクエスト
+type CreateSightingRequest struct {
+	SpotID     string    `json:"spot_id"`
+	ObservedAt time.Time `json:"observed_at"`
+	Quantity   string    `json:"quantity"`
+	Crowd      *string   `json:"crowd,omitempty"`
+}
+
 // CreatePollRequestはアンケート作成リクエスト
 type CreatePollRequest struct {
 	Options      []string `json:"options"`
@@ -108,3 +131,192 @@ type PaginatedPostsResponse struct {
 	Limit      int               `json:"limit"`
 	TotalPages int               `json:"totalPages"`
 }
+
+// PredictionHistoryEntryは特定の夜に対する、ある時点での予測内容
+type PredictionHistoryEntry struct {
+	FetchedAt       time.Time       `json:"fetched_at"`
+	ContentHash     string          `json:"content_hash"`
+	LeadDays        int             `json:"lead_days"`
+	PredictedAmount *float64        `json:"predicted_amount"`
+	Forecast        json.RawMessage `json:"forecast"`
+}
+
+// PredictionHistoryResponseは予測履歴APIのレスポンス
+type PredictionHistoryResponse struct {
+	Date    string                   `json:"date"`
+	Entries []PredictionHistoryEntry `json:"entries"`
+}
+
+// NightObservationは1晩分の現地情報の集計と、その夜に対する予測値
+type NightObservation struct {
+	NightDate           string     `json:"night_date"`
+	PredictedAmount     *float64   `json:"predicted_amount"`
+	PredictionFetchedAt *time.Time `json:"prediction_fetched_at,omitempty"`
+	ReportCount         int        `json:"report_count"`
+	GoodCount           int        `json:"good_count"`
+	BadCount            int        `json:"bad_count"`
+	PollVotes           int        `json:"poll_votes"`
+	PollScore           *float64   `json:"poll_score"`
+	TextScore           *float64   `json:"text_score"`
+	ObservedScore       *float64   `json:"observed_score"`
+	ComputedAt          time.Time  `json:"computed_at"`
+}
+
+// AccuracyMetricsは予報精度の指標
+type AccuracyMetrics struct {
+	Nights          int      `json:"nights"`
+	HitRate         *float64 `json:"hit_rate"`
+	Bias            *float64 `json:"bias"`
+	RankCorrelation *float64 `json:"rank_correlation"`
+}
+
+// AccuracyResponseは予報精度APIのレスポンス
+type AccuracyResponse struct {
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Metrics AccuracyMetrics    `json:"metrics"`
+	Nights  []NightObservation `json:"nights"`
+}
+
+// RefreshTaskResultは更新処理の中の1つの取得結果
+type RefreshTaskResult struct {
+	Spot  string `json:"spot,omitempty"`
+	Date  string `json:"date,omitempty"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"` // fetch_failed / convert_failed（詳細はサーバーのログにだけ出す）
+}
+
+// RefreshRunはキャッシュ更新1回分の記録
+type RefreshRun struct {
+	Trigger    string              `json:"trigger"`
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt time.Time           `json:"finished_at"`
+	DurationMs int64               `json:"duration_ms"`
+	Prediction RefreshTaskResult   `json:"prediction"`
+	Details    []RefreshTaskResult `json:"details"`
+	Succeeded  int                 `json:"succeeded"`
+	Failed     int                 `json:"failed"`
+}
+
+// RefreshStatusはキャッシュ更新ジョブの状態
+type RefreshStatus struct {
+	Running       bool        `json:"running"`
+	RunningSince  *time.Time  `json:"running_since,omitempty"`
+	Interval      string      `json:"interval"`
+	LastRun       *RefreshRun `json:"last_run"`
+	LastSuccessAt *time.Time  `json:"last_success_at"`
+	NextRunAt     *time.Time  `json:"next_run_at"`
+}
+
+// BakuwakiIndexは1夜分の爆湧き指数と、その要因ごとの内訳
+type BakuwakiIndex struct {
+	Date                string        `json:"date"`
+	SpotID              string        `json:"spot_id"`
+	Index               int           `json:"index"` // 0〜100
+	Level               int           `json:"level"` // 0（湧きなし）〜5（爆湧き）。シーズン外は-1
+	InSeason            bool          `json:"in_season"`
+	Factors             []IndexFactor `json:"factors"`
+	PredictionFetchedAt time.Time     `json:"prediction_fetched_at"`
+	DetailFetchedAt     *time.Time    `json:"detail_fetched_at"`
+}
+
+// IndexFactorは爆湧き指数を構成する要因1つ分
+type IndexFactor struct {
+	Name         string   `json:"name"`         // prediction, moon, tide, wind, precipitation
+	Value        *float64 `json:"value"`        // 評価に使った元の値（予測値、月齢、降水確率など）
+	Score        *float64 `json:"score"`        // 0〜1の評価値。データがない場合はnull
+	Weight       float64  `json:"weight"`       // 設定上の重み
+	Contribution float64  `json:"contribution"` // 指数への寄与（点）
+	Note         string   `json:"note,omitempty"`
+}
+
+// PushSubscriptionはWeb Push通知の購読情報と通知条件
+type PushSubscription struct {
+	DeviceID      string    `json:"-"`
+	Endpoint      string    `json:"endpoint"`
+	P256dh        string    `json:"-"`              // ブラウザの公開鍵（base64url）
+	Auth          string    `json:"-"`              // 認証シークレット（base64url）
+	MinLevel      int       `json:"min_level"`      // このレベル以上の予測になったら通知する（1〜5）
+	JumpThreshold float64   `json:"jump_threshold"` // 前回から予測値がこれ以上上がったら通知する
+	Spots         []string  `json:"spots"`          // 通知に含める地点
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Webhookは管理人が登録したイベントの送信先
+type Webhook struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	URL            string    `json:"url"`
+	Format         string    `json:"format"` // json, discord, slack, line
+	Events         []string  `json:"events"`
+	Secret         string    `json:"secret"` // 署名（X-Webhook-Signature）の鍵
+	AuthToken      string    `json:"-"`      // 設定されている場合はAuthorization: Bearerで送る
+	HasAuthToken   bool      `json:"has_auth_token"`
+	IndexThreshold int       `json:"index_threshold"` // index.aboveイベントの閾値（0〜100）
+	SpotID         string    `json:"spot_id"`         // index.aboveイベントの対象地点
+	Enabled        bool      `json:"enabled"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryはWebhookの送信1回分の記録
+type WebhookDelivery struct {
+	ID             int64     `json:"id"`
+	WebhookID      int       `json:"webhook_id"`
+	Event          string    `json:"event"`
+	Status         string    `json:"status"` // succeeded, failed
+	Attempts       int       `json:"attempts"`
+	ResponseStatus *int      `json:"response_status"`
+	Error          *string   `json:"error"`
+	DurationMs     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PredictionDiffは予測データの更新前後の差分
+type PredictionDiff struct {
+	FromFetchedAt time.Time           `json:"from_fetched_at"`
+	ToFetchedAt   time.Time           `json:"to_fetched_at"`
+	FromHash      string              `json:"from_hash"`
+	ToHash        string              `json:"to_hash"`
+	Days          []PredictionDayDiff `json:"days"` // 予測が変わった日（日付順）
+}
+
+// PredictionDayDiffは1日分の予測の差分
+type PredictionDayDiff struct {
+	Date           string                           `json:"date"`
+	Status         string                           `json:"status"` // "changed", "added"（新しく予測の対象になった）, "removed"（予測の対象から外れた）
+	PreviousAmount *float64                         `json:"previous_amount"`
+	CurrentAmount  *float64                         `json:"current_amount"`
+	AmountDelta    *float64                         `json:"amount_delta"`
+	PreviousLevel  *int                             `json:"previous_level"`
+	Level          *int                             `json:"level"`
+	Fields         map[string]PredictionFieldChange `json:"fields"` // predicted_amount以外で変わった項目（weather_codeなど）
+}
+
+// PredictionFieldChangeは予測項目1つの変化
+type PredictionFieldChange struct {
+	Previous interface{} `json:"previous"`
+	Current  interface{} `json:"current"`
+}
+
+// PredictionChangesResponseは予測の差分APIのレスポンス
+type PredictionChangesResponse struct {
+	Changes []PredictionDiff `json:"changes"` // 新しい順
+}
+
+// HealthCheckは/readyzの依存先ごとの確認結果
+type HealthCheck struct {
+	Status     string   `json:"status"` // "ok", "stale"（古いが使える）, "fail"
+	Message    string   `json:"message,omitempty"`
+	AgeSeconds *int64   `json:"age_seconds,omitempty"` // データを取得・読み込みしてからの経過秒数
+	LatencyMs  *int64   `json:"latency_ms,omitempty"`
+	Missing    []string `json:"missing,omitempty"` // 詳細データがまだない地点・日付（spot:YYYY-MM-DD）
+}
+
+// ReadinessResponseは/readyzのレスポンス
+type ReadinessResponse struct {
+	Status    string                 `json:"status"` // "ok", "degraded"（古いデータで応答中）, "fail"
+	CheckedAt time.Time              `json:"checked_at"`
+	Checks    map[string]HealthCheck `json:"checks"`
+}
\ No newline at end of file
@@ -0,0 +1,210 @@
+// backend/internal/night/night.go
+package night
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/astronomy"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// 暗さの判定
+const (
+	darkSunAltitude  = -18.0 // 太陽がこれより低ければ天文薄明も終わっている
+	darkMoonAltitude = 0.0   // 月がこれより低ければ沈んでいる
+	darkScanStep     = 10 * time.Minute
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// Config は「夜」とみなす時間帯（JST）
+// 開始時刻から翌日の終了時刻までを1夜とし、開始した日の日付で呼ぶ
+type Config struct {
+	StartHour int
+	EndHour   int
+}
+
+// DefaultConfig は既定の夜の時間帯（20時〜翌5時）
+func DefaultConfig() Config {
+	return Config{StartHour: 20, EndHour: 5}
+}
+
+// ParseWindow は「開始時-終了時」（例: 20-5）の形式の夜の時間帯を読み込む
+func ParseWindow(v string) (Config, error) {
+	parts := strings.SplitN(v, "-", 2)
+	if len(parts) != 2 {
+		return Config{}, fmt.Errorf("夜の時間帯の形式が不正です（例: 20-5）: %q", v)
+	}
+	start, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	end, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil {
+		return Config{}, fmt.Errorf("夜の時間帯の形式が不正です（例: 20-5）: %q", v)
+	}
+	cfg := Config{StartHour: start, EndHour: end}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate は開始が12〜23時、終了が翌日の0〜11時であることを検証する
+func (c Config) Validate() error {
+	if c.StartHour < 12 || c.StartHour > 23 || c.EndHour < 0 || c.EndHour > 11 {
+		return fmt.Errorf("夜の時間帯が不正です（開始は12〜23時、終了は0〜11時）: %d-%d", c.StartHour, c.EndHour)
+	}
+	return nil
+}
+
+// Window は指定日に始まる夜の開始・終了時刻を返す
+func (c Config) Window(date time.Time) (time.Time, time.Time) {
+	d := date.In(jst)
+	start := time.Date(d.Year(), d.Month(), d.Day(), c.StartHour, 0, 0, 0, jst)
+	end := time.Date(d.Year(), d.Month(), d.Day()+1, c.EndHour, 0, 0, 0, jst)
+	return start, end
+}
+
+// Summarize は詳細データから夜の間の気象・潮汐・暗さをまとめる
+func Summarize(cfg Config, detail *model.DetailData) (*model.NightSummary, error) {
+	date, err := time.ParseInLocation("2006-01-02", detail.Date, jst)
+	if err != nil {
+		return nil, fmt.Errorf("日付の形式が不正です: %w", err)
+	}
+	start, end := cfg.Window(date)
+	summary := &model.NightSummary{
+		Date:       detail.Date,
+		SpotID:     detail.Spot.ID,
+		Start:      start,
+		End:        end,
+		TideSource: detail.Tide.Source,
+		TideEvents: []model.TideEvent{},
+		Moon:       detail.Tide.Moon,
+		Hours:      []model.NightHour{},
+	}
+
+	curve, events := detail.Tide.Curve, detail.Tide.Events
+	if detail.NextTide != nil {
+		curve = append(append([]model.TidePoint{}, curve...), detail.NextTide.Curve...)
+		events = append(append([]model.TideEvent{}, events...), detail.NextTide.Events...)
+	}
+	lat, lon := detail.Spot.Latitude, detail.Spot.Longitude
+
+	// 気象
+	var tempSum, speedSum, x, y float64
+	summary.TemperatureMin = math.Inf(1)
+	summary.TemperatureMax = math.Inf(-1)
+	for _, w := range detail.Weather {
+		if w.Time.Before(start) || !w.Time.Before(end) {
+			continue
+		}
+		mid := w.Time.Add(30 * time.Minute)
+		hour := model.NightHour{
+			Time:                     w.Time,
+			Temperature:              w.Temperature,
+			Precipitation:            w.Precipitation,
+			PrecipitationProbability: w.PrecipitationProbability,
+			WeatherCode:              w.WeatherCode,
+			WindSpeed:                w.WindSpeed,
+			WindDirection:            w.WindDirection,
+			TideHeight:               TideHeightAt(curve, w.Time),
+			SunAltitude:              round1(astronomy.SunAltitude(lat, lon, mid)),
+			MoonAltitude:             round1(astronomy.MoonAltitude(lat, lon, mid)),
+		}
+		hour.Dark = isDark(lat, lon, mid)
+		summary.Hours = append(summary.Hours, hour)
+
+		tempSum += w.Temperature
+		summary.TemperatureMin = math.Min(summary.TemperatureMin, w.Temperature)
+		summary.TemperatureMax = math.Max(summary.TemperatureMax, w.Temperature)
+		summary.PrecipitationTotal += w.Precipitation
+		summary.PrecipitationProbabilityMax = math.Max(summary.PrecipitationProbabilityMax, w.PrecipitationProbability)
+		speedSum += w.WindSpeed
+		summary.WindSpeedMax = math.Max(summary.WindSpeedMax, w.WindSpeed)
+		rad := w.WindDirection * math.Pi / 180
+		x += w.WindSpeed * math.Sin(rad)
+		y += w.WindSpeed * math.Cos(rad)
+	}
+	if n := float64(len(summary.Hours)); n > 0 {
+		summary.TemperatureAvg = round1(tempSum / n)
+		summary.WindSpeedAvg = round1(speedSum / n)
+		summary.WindDirection = round1(math.Mod(math.Atan2(x, y)*180/math.Pi+360, 360))
+		summary.PrecipitationTotal = round1(summary.PrecipitationTotal)
+	} else {
+		summary.TemperatureMin, summary.TemperatureMax = 0, 0
+	}
+
+	// 潮汐（満干の時刻を優先し、なければ潮位曲線から最高・最低を求める）
+	for _, e := range events {
+		if e.Time.Before(start) || e.Time.After(end) {
+			continue
+		}
+		summary.TideEvents = append(summary.TideEvents, e)
+		p := model.TidePoint{Time: e.Time, Height: e.Height}
+		if summary.LowestTide == nil || p.Height < summary.LowestTide.Height {
+			summary.LowestTide = &p
+		}
+		if summary.HighestTide == nil || p.Height > summary.HighestTide.Height {
+			summary.HighestTide = &p
+		}
+	}
+	for _, c := range curve {
+		if c.Time.Before(start) || c.Time.After(end) {
+			continue
+		}
+		p := c
+		if summary.LowestTide == nil || p.Height < summary.LowestTide.Height {
+			summary.LowestTide = &p
+		}
+		if summary.HighestTide == nil || p.Height > summary.HighestTide.Height {
+			summary.HighestTide = &p
+		}
+	}
+
+	// 暗い時間帯
+	summary.DarkPeriods = []model.TimeRange{}
+	var current *model.TimeRange
+	for t := start; t.Before(end); t = t.Add(darkScanStep) {
+		if isDark(lat, lon, t.Add(darkScanStep/2)) {
+			if current == nil {
+				current = &model.TimeRange{Start: t}
+			}
+			current.End = t.Add(darkScanStep)
+			summary.DarkMinutes += int(darkScanStep / time.Minute)
+		} else if current != nil {
+			summary.DarkPeriods = append(summary.DarkPeriods, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		summary.DarkPeriods = append(summary.DarkPeriods, *current)
+	}
+	return summary, nil
+}
+
+func isDark(lat, lon float64, t time.Time) bool {
+	return astronomy.SunAltitude(lat, lon, t) < darkSunAltitude && astronomy.MoonAltitude(lat, lon, t) < darkMoonAltitude
+}
+
+// TideHeightAt は潮位曲線を線形補間して指定時刻の潮位を返す（範囲外の場合はnil）
+func TideHeightAt(curve []model.TidePoint, t time.Time) *float64 {
+	for i := 1; i < len(curve); i++ {
+		a, b := curve[i-1], curve[i]
+		if t.Before(a.Time) || t.After(b.Time) {
+			continue
+		}
+		h := a.Height
+		if span := b.Time.Sub(a.Time); span > 0 {
+			h += (b.Height - a.Height) * float64(t.Sub(a.Time)) / float64(span)
+		}
+		h = round1(h)
+		return &h
+	}
+	return nil
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
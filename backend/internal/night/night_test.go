@@ -0,0 +1,106 @@
+// backend/internal/night/night_test.go
+package night
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// newTestDetail は2024年1月11日（新月で月は夕方に沈む）の詳細データを作る
+// 潮位は一定で、23:40に干潮がある
+func newTestDetail() *model.DetailData {
+	date := time.Date(2024, 1, 11, 0, 0, 0, 0, jst)
+	detail := &model.DetailData{
+		Date: "2024-01-11",
+		Spot: model.DetailSpot{ID: "iwasehama", Latitude: 36.76, Longitude: 137.24},
+		Tide: model.TideDay{
+			Source: "tide736",
+			Events: []model.TideEvent{{Type: model.TideEbb, Time: date.Add(23*time.Hour + 40*time.Minute), Height: 2}},
+		},
+	}
+	for h := 0; h < 48; h++ {
+		detail.Weather = append(detail.Weather, model.HourlyWeather{
+			Time:        date.Add(time.Duration(h) * time.Hour),
+			Temperature: float64(h % 24),
+			WindSpeed:   2,
+		})
+	}
+	for m := 0; m <= 48*60; m += 20 {
+		detail.Tide.Curve = append(detail.Tide.Curve, model.TidePoint{Time: date.Add(time.Duration(m) * time.Minute), Height: 10})
+	}
+	return detail
+}
+
+// TestSummarize は新月の夜について、気象の集計・最低潮位・暗い時間帯をテストする
+func TestSummarize(t *testing.T) {
+	detail := newTestDetail()
+	summary, err := Summarize(DefaultConfig(), detail)
+	if err != nil {
+		t.Fatalf("まとめの作成に失敗: %v", err)
+	}
+	if len(summary.Hours) != 9 || summary.Hours[0].Time.Hour() != 20 {
+		t.Fatalf("夜の時間数 = %d, want 9（20時〜4時）", len(summary.Hours))
+	}
+	if summary.TemperatureMin != 0 || summary.TemperatureMax != 23 {
+		t.Errorf("気温の最低・最高 = %v, %v, want 0, 23", summary.TemperatureMin, summary.TemperatureMax)
+	}
+	if summary.LowestTide == nil || summary.LowestTide.Height != 2 || summary.LowestTide.Time.Format("15:04") != "23:40" {
+		t.Errorf("最低潮位 = %+v, want 23:40 2cm", summary.LowestTide)
+	}
+	if summary.DarkMinutes != 9*60 || len(summary.DarkPeriods) != 1 {
+		t.Errorf("暗い時間 = %d分（%d区間）, want 540分（1区間）", summary.DarkMinutes, len(summary.DarkPeriods))
+	}
+	if h := summary.Hours[0].TideHeight; h == nil || *h != 10 {
+		t.Errorf("20時の潮位 = %v, want 10", h)
+	}
+}
+
+// TestParseWindow は夜の時間帯の読み込みをテストする
+func TestParseWindow(t *testing.T) {
+	cfg, err := ParseWindow("21-4")
+	if err != nil || cfg.StartHour != 21 || cfg.EndHour != 4 {
+		t.Errorf("ParseWindow() = %+v, %v", cfg, err)
+	}
+	if _, err := ParseWindow("5-20"); err == nil {
+		t.Error("開始と終了が逆の設定がエラーになりませんでした")
+	}
+	if _, err := ParseWindow("20"); err == nil {
+		t.Error("形式が不正な設定がエラーになりませんでした")
+	}
+}
+
+// 時間ごとの評価の重みと風速の閾値（実際は爆湧き指数の設定から渡す）
+var (
+	testWeights = model.TimelineFactors{Tide: 0.25, Wind: 0.3, Rain: 0.25, Darkness: 0.2}
+	testWind    = WindSpeeds{Calm: 2, Strong: 8}
+)
+
+// TestBuildTimeline は雨の時間を見送りとし、残りの時間からおすすめの時間帯を選ぶことをテストする
+func TestBuildTimeline(t *testing.T) {
+	detail := newTestDetail()
+	for i := range detail.Weather {
+		if h := detail.Weather[i].Time.Hour(); h >= 20 && h < 22 {
+			detail.Weather[i].PrecipitationProbability = 90
+		}
+	}
+	// 海からの強風（岩瀬浜は北向き）
+	detail.Weather[24+4].WindSpeed = 9
+
+	timeline, err := BuildTimeline(DefaultConfig(), testWeights, testWind, detail, 0)
+	if err != nil {
+		t.Fatalf("タイムラインの作成に失敗: %v", err)
+	}
+	verdicts := ""
+	for _, h := range timeline.Hours {
+		verdicts += h.Verdict[:1]
+	}
+	// 20〜21時は雨、4時は強風で見送り
+	if verdicts != "nnggggggn" {
+		t.Errorf("判定 = %q, want %q", verdicts, "nnggggggn")
+	}
+	if timeline.Summary != "おすすめは22:00〜04:00" {
+		t.Errorf("Summary = %q", timeline.Summary)
+	}
+}
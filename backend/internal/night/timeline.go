@@ -0,0 +1,189 @@
+// backend/internal/night/timeline.go
+package night
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// 判定の閾値
+const (
+	goScore    = 60
+	maybeScore = 40
+)
+
+// 風向の評価
+const (
+	onshoreAngle  = 45  // 浜の向きとの差がこの角度以内なら海からの風
+	offshoreAngle = 135 // 浜の向きとの差がこの角度以上なら陸からの風
+)
+
+// WindSpeeds は風速の評価に使う閾値（m/s、爆湧き指数の設定から渡す）
+type WindSpeeds struct {
+	Calm   float64 // この風速以下は満点
+	Strong float64 // この風速以上は0点
+}
+
+// 満潮・干潮の前後とみなす時間
+const tideEventMargin = time.Hour
+
+// BuildTimeline は夜の時間ごとに潮・風・雨・暗さを評価し、行き時かどうかを判定する
+// weightsは潮・風・雨・暗さの重み、windは風速の閾値（どちらも爆湧き指数の設定から渡す）、shoreBearingは浜から海を向いた方角（度）
+func BuildTimeline(cfg Config, weights model.TimelineFactors, wind WindSpeeds, detail *model.DetailData, shoreBearing float64) (*model.Timeline, error) {
+	summary, err := Summarize(cfg, detail)
+	if err != nil {
+		return nil, err
+	}
+	curve, events := detail.Tide.Curve, detail.Tide.Events
+	if detail.NextTide != nil {
+		curve = append(append([]model.TidePoint{}, curve...), detail.NextTide.Curve...)
+		events = append(append([]model.TideEvent{}, events...), detail.NextTide.Events...)
+	}
+
+	timeline := &model.Timeline{
+		Date:   summary.Date,
+		SpotID: summary.SpotID,
+		Start:  summary.Start,
+		End:    summary.End,
+		Hours:  []model.TimelineHour{},
+	}
+	for _, h := range summary.Hours {
+		var factors model.TimelineFactors
+		var reasons []string
+
+		var reason string
+		factors.Tide, reason = tideScore(h.Time, curve, events)
+		reasons = append(reasons, reason)
+		factors.Wind, reason = windScore(wind, h.WindSpeed, h.WindDirection, shoreBearing)
+		reasons = append(reasons, reason)
+		factors.Rain = 1 - math.Min(h.PrecipitationProbability, 100)/100
+		reasons = append(reasons, fmt.Sprintf("降水確率%.0f%%", h.PrecipitationProbability))
+		factors.Darkness, reason = darknessScore(h)
+		reasons = append(reasons, reason)
+
+		total := weights.Tide + weights.Wind + weights.Rain + weights.Darkness
+		score := (weights.Tide*factors.Tide + weights.Wind*factors.Wind +
+			weights.Rain*factors.Rain + weights.Darkness*factors.Darkness) / total
+		hour := model.TimelineHour{
+			Time:    h.Time,
+			Score:   int(math.Round(score * 100)),
+			Factors: roundFactors(factors),
+			Reasons: reasons,
+		}
+		switch {
+		case factors.Rain <= 0.2 || factors.Wind == 0:
+			// 大雨・強風の時間は他の条件が良くても見送る
+			hour.Verdict = model.VerdictNoGo
+		case hour.Score >= goScore:
+			hour.Verdict = model.VerdictGo
+		case hour.Score >= maybeScore:
+			hour.Verdict = model.VerdictMaybe
+		default:
+			hour.Verdict = model.VerdictNoGo
+		}
+		timeline.Hours = append(timeline.Hours, hour)
+	}
+
+	timeline.BestWindow = bestWindow(timeline.Hours)
+	if timeline.BestWindow != nil {
+		timeline.Summary = fmt.Sprintf("おすすめは%s〜%s", timeline.BestWindow.Start.Format("15:04"), timeline.BestWindow.End.Format("15:04"))
+	} else {
+		timeline.Summary = "おすすめの時間帯はありません"
+	}
+	return timeline, nil
+}
+
+// tideScore は上げ潮・満潮前後を高く、干潮前後を低く評価する
+func tideScore(t time.Time, curve []model.TidePoint, events []model.TideEvent) (float64, string) {
+	for _, e := range events {
+		if e.Time.Sub(t).Abs() > tideEventMargin {
+			continue
+		}
+		if e.Type == model.TideFlood {
+			return 1, "満潮前後（" + e.Time.Format("15:04") + "）"
+		}
+		return 0.3, "干潮前後（" + e.Time.Format("15:04") + "）"
+	}
+	now, next := TideHeightAt(curve, t), TideHeightAt(curve, t.Add(time.Hour))
+	if now == nil || next == nil {
+		return 0.5, "潮位データなし"
+	}
+	if *next > *now {
+		return 0.9, "上げ潮"
+	}
+	return 0.5, "下げ潮"
+}
+
+// windScore は風が弱いほど高く評価し、海からの強い風（波が高くなる）を減点する
+func windScore(wind WindSpeeds, speed, direction, shoreBearing float64) (float64, string) {
+	s := math.Min(math.Max((wind.Strong-speed)/(wind.Strong-wind.Calm), 0), 1)
+	if speed <= wind.Calm {
+		return s, fmt.Sprintf("ほぼ無風（%.1fm/s）", speed)
+	}
+	factor, name := shoreWind(direction, shoreBearing)
+	return s * factor, fmt.Sprintf("%s %.1fm/s", name, speed)
+}
+
+// ShoreWindFactor は浜の向きに対する風向の評価（海からの風0.7、横風0.85、陸からの風1）を返す
+// directionは風が吹いてくる方角、shoreBearingは浜から海を向いた方角（度）
+func ShoreWindFactor(direction, shoreBearing float64) float64 {
+	factor, _ := shoreWind(direction, shoreBearing)
+	return factor
+}
+
+func shoreWind(direction, shoreBearing float64) (float64, string) {
+	diff := math.Abs(math.Mod(direction-shoreBearing+540, 360) - 180)
+	switch {
+	case diff <= onshoreAngle:
+		return 0.7, "海からの風"
+	case diff >= offshoreAngle:
+		return 1, "陸からの風"
+	default:
+		return 0.85, "横風"
+	}
+}
+
+// darknessScore は月が沈み空が暗いほど高く評価する
+func darknessScore(h model.NightHour) (float64, string) {
+	switch {
+	case h.Dark:
+		return 1, "月なしで暗い"
+	case h.SunAltitude >= darkSunAltitude:
+		return 0.3, "薄明"
+	default:
+		// 月が高いほど明るい
+		return math.Max(0.2, 0.7-0.5*math.Sin(math.Min(h.MoonAltitude, 90)*math.Pi/180)), "月明かりあり"
+	}
+}
+
+// bestWindow はgoの時間が最も長く続く時間帯を返す（同じ長さなら平均点が高い方）
+func bestWindow(hours []model.TimelineHour) *model.TimeRange {
+	var best *model.TimeRange
+	bestLen, bestAvg := 0, 0.0
+	for i := 0; i < len(hours); {
+		if hours[i].Verdict != model.VerdictGo {
+			i++
+			continue
+		}
+		j, sum := i, 0
+		for j < len(hours) && hours[j].Verdict == model.VerdictGo {
+			sum += hours[j].Score
+			j++
+		}
+		length, avg := j-i, float64(sum)/float64(j-i)
+		if length > bestLen || (length == bestLen && avg > bestAvg) {
+			best = &model.TimeRange{Start: hours[i].Time, End: hours[j-1].Time.Add(time.Hour)}
+			bestLen, bestAvg = length, avg
+		}
+		i = j
+	}
+	return best
+}
+
+func roundFactors(f model.TimelineFactors) model.TimelineFactors {
+	r := func(v float64) float64 { return math.Round(v*100) / 100 }
+	return model.TimelineFactors{Tide: r(f.Tide), Wind: r(f.Wind), Rain: r(f.Rain), Darkness: r(f.Darkness)}
+}
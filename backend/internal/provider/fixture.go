@@ -0,0 +1,187 @@
+// backend/internal/provider/fixture.go
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// Fixture はディスク上に記録したJSONを返すオフライン用のプロバイダ
+//
+// ディレクトリ構成:
+//
+//	prediction.json                 予測データ
+//	weather/{spotID}/{date}.json    地点・日付ごとの気象データ（任意）
+//	weather.json                    上記がない場合に使う気象データ
+//	tide/{pc}-{hc}/{date}.json      日付ごとの潮汐データ（任意。pc・hcはspot.TidePrefCode・TideHarborCode）
+//	tide.json                       上記がない場合に使う潮汐データ
+//
+// 日付指定のないファイルを使う場合は、記録時の日付を要求された日付にずらして返す
+// （毎日同じ記録データでローカル環境を動かせるようにするため）
+type Fixture struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFixture は新しいFixtureを初期化する
+func NewFixture(dir string) *Fixture {
+	return &Fixture{dir: dir, now: time.Now}
+}
+
+func (f *Fixture) Name() string { return "fixture" }
+
+func (f *Fixture) FetchPrediction() ([]byte, error) {
+	body, err := os.ReadFile(filepath.Join(f.dir, "prediction.json"))
+	if err != nil {
+		return nil, fmt.Errorf("予測データのフィクスチャ読み込み失敗: %w", err)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(body, &entries); err != nil || len(entries) == 0 {
+		return body, nil
+	}
+	// 先頭の日付が今日（JST）になるようにずらす
+	first, ok := entries[0]["date"].(string)
+	if !ok {
+		return body, nil
+	}
+	offset, err := dayOffset(first, f.now().In(jst))
+	if err != nil {
+		return body, nil
+	}
+	for _, e := range entries {
+		if d, ok := e["date"].(string); ok {
+			e["date"] = shiftDate(d, offset)
+		}
+	}
+	return json.Marshal(entries)
+}
+
+func (f *Fixture) FetchWeather(s spot.Spot, targetDate time.Time) (map[string]interface{}, error) {
+	dateStr := targetDate.Format("2006-01-02")
+	data, exact, err := f.load(filepath.Join("weather", s.ID, dateStr+".json"), "weather.json")
+	if err != nil || exact {
+		return data, err
+	}
+	hourly, ok := data["hourly"].(map[string]interface{})
+	if !ok {
+		return data, nil
+	}
+	times, ok := hourly["time"].([]interface{})
+	if !ok || len(times) == 0 {
+		return data, nil
+	}
+	first, _ := times[0].(string)
+	offset, err := dayOffset(first, targetDate)
+	if err != nil {
+		return data, nil
+	}
+	for i, t := range times {
+		if ts, ok := t.(string); ok {
+			times[i] = shiftDate(ts, offset)
+		}
+	}
+	return data, nil
+}
+
+func (f *Fixture) FetchTide(s spot.Spot, targetDate time.Time) (map[string]interface{}, error) {
+	dateStr := targetDate.Format("2006-01-02")
+	station := fmt.Sprintf("%d-%d", spot.TidePrefCode, spot.TideHarborCode)
+	data, exact, err := f.load(filepath.Join("tide", station, dateStr+".json"), "tide.json")
+	if err != nil || exact {
+		return data, err
+	}
+	tide, ok := data["tide"].(map[string]interface{})
+	if !ok {
+		return data, nil
+	}
+	chart, ok := tide["chart"].(map[string]interface{})
+	if !ok {
+		return data, nil
+	}
+	// chartのキー（記録時の日付）を要求された日付に付け替える
+	shifted := make(map[string]interface{}, len(chart))
+	for recorded, day := range chart {
+		offset, err := dayOffset(recorded, targetDate)
+		if err != nil {
+			shifted[recorded] = day
+			continue
+		}
+		shiftUnixTimes(day, offset)
+		shifted[shiftDate(recorded, offset)] = day
+	}
+	tide["chart"] = shifted
+	return data, nil
+}
+
+// load は日付指定のファイルを優先して読み込み、なければ共通ファイルを読み込む
+// 日付指定のファイルを読み込んだ場合はexactがtrueになる
+func (f *Fixture) load(exactPath, fallbackPath string) (data map[string]interface{}, exact bool, err error) {
+	body, err := os.ReadFile(filepath.Join(f.dir, exactPath))
+	if err == nil {
+		exact = true
+	} else if errors.Is(err, fs.ErrNotExist) {
+		body, err = os.ReadFile(filepath.Join(f.dir, fallbackPath))
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("フィクスチャの読み込み失敗: %w", err)
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, false, fmt.Errorf("フィクスチャのJSONが不正です: %w", err)
+	}
+	return data, exact, nil
+}
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// dayOffset は記録時の日付（"2006-01-02"で始まる文字列）から対象日までの日数を返す
+func dayOffset(recorded string, target time.Time) (int, error) {
+	if len(recorded) < 10 {
+		return 0, fmt.Errorf("日付の形式が不正です: %s", recorded)
+	}
+	r, err := time.Parse("2006-01-02", recorded[:10])
+	if err != nil {
+		return 0, err
+	}
+	t := time.Date(target.Year(), target.Month(), target.Day(), 0, 0, 0, 0, time.UTC)
+	return int(t.Sub(r).Hours() / 24), nil
+}
+
+// shiftDate は"2006-01-02"で始まる文字列の日付部分をoffset日ずらす
+func shiftDate(s string, offset int) string {
+	if len(s) < 10 {
+		return s
+	}
+	d, err := time.Parse("2006-01-02", s[:10])
+	if err != nil {
+		return s
+	}
+	return d.AddDate(0, 0, offset).Format("2006-01-02") + s[10:]
+}
+
+// shiftUnixTimes は潮汐データ内のunix（ミリ秒）をoffset日ずらす
+func shiftUnixTimes(day interface{}, offset int) {
+	d, ok := day.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, key := range []string{"tide", "flood", "edd"} {
+		points, ok := d[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, p := range points {
+			if m, ok := p.(map[string]interface{}); ok {
+				if unix, ok := m["unix"].(float64); ok {
+					m["unix"] = unix + float64(offset)*24*60*60*1000
+				}
+			}
+		}
+	}
+}
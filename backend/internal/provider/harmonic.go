@@ -0,0 +1,117 @@
+// backend/internal/provider/harmonic.go
+package provider
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/astronomy"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/harmonic"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// 潮位曲線の間隔（tide736.netと揃える）
+const harmonicCurveStep = 20 * time.Minute
+
+// HarmonicTide は調和定数から潮汐をローカルで推算する
+// tide736.netが使えないときの代替として使い、レスポンスには"source": "computed"を付ける
+type HarmonicTide struct {
+	table *harmonic.Table
+}
+
+// NewHarmonicTide は新しいHarmonicTideを初期化する
+func NewHarmonicTide(table *harmonic.Table) *HarmonicTide {
+	return &HarmonicTide{table: table}
+}
+
+func (h *HarmonicTide) Name() string { return "computed" }
+
+// FetchTide はtide736.netと同じ形式で1日分の推算結果を返す
+func (h *HarmonicTide) FetchTide(s spot.Spot, targetDate time.Time) (map[string]interface{}, error) {
+	station, ok := h.table.Station(spot.TidePrefCode, spot.TideHarborCode)
+	if !ok {
+		return nil, fmt.Errorf("港(pc=%d, hc=%d)の調和定数がありません", spot.TidePrefCode, spot.TideHarborCode)
+	}
+	d := targetDate.In(jst)
+	start := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, jst)
+	end := start.Add(24 * time.Hour)
+
+	point := func(t time.Time, height float64) map[string]interface{} {
+		clock := t.Format("15:04")
+		if t.Equal(end) {
+			clock = "24:00"
+		}
+		return map[string]interface{}{
+			"time": clock,
+			"unix": t.UnixMilli(),
+			"cm":   math.Round(height*10) / 10,
+		}
+	}
+	curve := []interface{}{}
+	for _, p := range station.Curve(start, end, harmonicCurveStep) {
+		curve = append(curve, point(p.Time, p.Height))
+	}
+	flood, ebb := []interface{}{}, []interface{}{}
+	for _, e := range station.Extrema(start, end) {
+		if e.High {
+			flood = append(flood, point(e.Time, e.Height))
+		} else {
+			ebb = append(ebb, point(e.Time, e.Height))
+		}
+	}
+
+	astro := astronomy.Day(s.Latitude, s.Longitude, start)
+	clock := func(t *time.Time) string {
+		if t == nil {
+			return "--:--"
+		}
+		return t.Format("15:04")
+	}
+	dateStr := start.Format("2006-01-02")
+	return map[string]interface{}{
+		"status":  1,
+		"message": "調和定数から推算した潮汐です",
+		"source":  "computed",
+		"tide": map[string]interface{}{
+			"port": map[string]interface{}{
+				"harbor_namej": station.Name,
+				"latitude":     s.Latitude,
+				"longitude":    s.Longitude,
+			},
+			"chart": map[string]interface{}{
+				dateStr: map[string]interface{}{
+					"moon": map[string]interface{}{
+						"age":   fmt.Sprintf("%.1f", astro.MoonAge),
+						"title": tideName(astro.MoonAge),
+						"illum": astro.MoonIllumination,
+					},
+					"sun": map[string]interface{}{
+						"rise": clock(astro.Sunrise),
+						"set":  clock(astro.Sunset),
+					},
+					"flood": flood,
+					"edd":   ebb,
+					"tide":  curve,
+				},
+			},
+		},
+	}, nil
+}
+
+// tideName は月齢から潮名を求める（月齢から求めた旧暦の日付による慣用的な区分）
+func tideName(moonAge float64) string {
+	lunarDay := int(moonAge)%30 + 1
+	switch lunarDay {
+	case 1, 2, 3, 14, 15, 16, 17, 29, 30:
+		return "大潮"
+	case 7, 8, 9, 21, 22, 23:
+		return "小潮"
+	case 10, 24:
+		return "長潮"
+	case 11, 25:
+		return "若潮"
+	default:
+		return "中潮"
+	}
+}
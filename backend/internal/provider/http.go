@@ -0,0 +1,102 @@
+// backend/internal/provider/http.go
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+const httpTimeout = 30 * time.Second
+
+// PredictionAPI は機械学習の予測APIから予測データを取得する
+type PredictionAPI struct {
+	url    string
+	client *http.Client
+}
+
+// NewPredictionAPI は新しいPredictionAPIを初期化する
+func NewPredictionAPI(url string) *PredictionAPI {
+	return &PredictionAPI{url: url, client: &http.Client{Timeout: httpTimeout}}
+}
+
+func (p *PredictionAPI) Name() string { return "prediction_api" }
+
+func (p *PredictionAPI) FetchPrediction() ([]byte, error) {
+	resp, err := p.client.Get(p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("予測APIが正常なステータスを返しませんでした: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("予測APIのレスポンスボディの読み取りに失敗しました: %w", err)
+	}
+	return body, nil
+}
+
+// OpenMeteo はOpen-Meteoから時間別の気象データを取得する
+type OpenMeteo struct {
+	client *http.Client
+}
+
+// NewOpenMeteo は新しいOpenMeteoを初期化する
+func NewOpenMeteo() *OpenMeteo {
+	return &OpenMeteo{client: &http.Client{Timeout: httpTimeout}}
+}
+
+func (o *OpenMeteo) Name() string { return "open_meteo" }
+
+func (o *OpenMeteo) FetchWeather(s spot.Spot, targetDate time.Time) (map[string]interface{}, error) {
+	startDate := targetDate.Format("2006-01-02")
+	endDate := targetDate.AddDate(0, 0, 1).Format("2006-01-02")
+	weatherApiUrl := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&hourly=temperature_2m,precipitation,precipitation_probability,weather_code,wind_speed_10m,wind_direction_10m&timezone=Asia%%2FTokyo&wind_speed_unit=ms&start_date=%s&end_date=%s", s.Latitude, s.Longitude, startDate, endDate)
+	return getJSON(o.client, weatherApiUrl, "気象API")
+}
+
+// Tide736 はtide736.netから潮汐データを取得する
+type Tide736 struct {
+	client *http.Client
+}
+
+// NewTide736 は新しいTide736を初期化する
+func NewTide736() *Tide736 {
+	return &Tide736{client: &http.Client{Timeout: httpTimeout}}
+}
+
+func (t *Tide736) Name() string { return "tide736" }
+
+func (t *Tide736) FetchTide(s spot.Spot, targetDate time.Time) (map[string]interface{}, error) {
+	year := targetDate.Year()
+	month := int(targetDate.Month())
+	day := targetDate.Day()
+	tideApiUrl := fmt.Sprintf("https://tide736.net/api/get_tide.php?pc=%d&hc=%d&yr=%d&mn=%d&dy=%d&rg=day", spot.TidePrefCode, spot.TideHarborCode, year, month, day)
+	return getJSON(t.client, tideApiUrl, "潮汐API")
+}
+
+func getJSON(client *http.Client, url, apiName string) (map[string]interface{}, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%sが正常なステータスを返しませんでした: %d", apiName, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
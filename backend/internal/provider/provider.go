@@ -0,0 +1,57 @@
+// backend/internal/provider/provider.go
+package provider
+
+import (
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// PredictionProvider は身投げ量の予測データ（JSON配列）を提供する
+type PredictionProvider interface {
+	Name() string
+	FetchPrediction() ([]byte, error)
+}
+
+// WeatherProvider は地点・日付ごとの時間別気象データを提供する
+// 戻り値はOpen-Meteoのレスポンス形式
+type WeatherProvider interface {
+	Name() string
+	FetchWeather(s spot.Spot, date time.Time) (map[string]interface{}, error)
+}
+
+// TideProvider は地点・日付ごとの潮汐データを提供する
+// 戻り値はtide736.netのレスポンス形式
+type TideProvider interface {
+	Name() string
+	FetchTide(s spot.Spot, date time.Time) (map[string]interface{}, error)
+}
+
+// Set はCacheManagerが使うデータ取得元の組
+type Set struct {
+	Prediction PredictionProvider
+	Weather    WeatherProvider
+	Tide       TideProvider
+	// TideFallback はTideが失敗したときに使う潮汐の取得元（nilの場合は代替なし）
+	// 出典のある調和定数（TIDE_STATIONS_PATH）を読み込んだ場合だけ設定する
+	TideFallback TideProvider
+}
+
+// NewHTTPSet は外部APIから取得する本番用のSetを返す
+func NewHTTPSet(predictionURL string) Set {
+	return Set{
+		Prediction: NewPredictionAPI(predictionURL),
+		Weather:    NewOpenMeteo(),
+		Tide:       NewTide736(),
+	}
+}
+
+// NewFixtureSet はディスク上の記録済みJSONを返すオフライン用のSetを返す
+func NewFixtureSet(dir string) Set {
+	f := NewFixture(dir)
+	return Set{
+		Prediction: f,
+		Weather:    f,
+		Tide:       f,
+	}
+}
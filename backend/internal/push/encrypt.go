@@ -0,0 +1,92 @@
+// backend/internal/push/encrypt.go
+package push
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// aes128gcmのレコードサイズ。通知の本文は1レコードに収める
+const recordSize = 4096
+
+// encrypt はペイロードをブラウザの公開鍵と認証シークレットで暗号化する（RFC 8291, RFC 8188）
+func encrypt(plaintext, uaPublic, authSecret []byte) ([]byte, error) {
+	if len(plaintext)+17+16 > recordSize {
+		return nil, fmt.Errorf("通知の本文が大きすぎます: %dバイト", len(plaintext))
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("購読の公開鍵が不正です: %w", err)
+	}
+	if len(authSecret) != 16 {
+		return nil, fmt.Errorf("購読の認証シークレットの長さが不正です: %dバイト", len(authSecret))
+	}
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	ecdhSecret, err := asKey.ECDH(uaKey)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asKey.PublicKey().Bytes()
+
+	cek, nonce, err := deriveKeys(ecdhSecret, authSecret, salt, uaPublic, asPublic)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// ヘッダー: salt(16) | rs(4) | idlen(1) | keyid(送信側の公開鍵)
+	header := make([]byte, 0, 21+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// 最後のレコードであることを示す区切り(0x02)を付けて暗号化する
+	record := append(append([]byte{}, plaintext...), 0x02)
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// deriveKeys はECDHの共有鍵からコンテンツ暗号鍵とナンスを導出する
+// 受信側（テストの復号）でも同じ手順を使う
+func deriveKeys(ecdhSecret, authSecret, salt, uaPublic, asPublic []byte) (cek, nonce []byte, err error) {
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	prkKey, err := hkdf.Extract(sha256.New, ecdhSecret, authSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cek, err = hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16); err != nil {
+		return nil, nil, err
+	}
+	if nonce, err = hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12); err != nil {
+		return nil, nil, err
+	}
+	return cek, nonce, nil
+}
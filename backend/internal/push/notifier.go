@@ -0,0 +1,265 @@
+// backend/internal/push/notifier.go
+package push
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// 購読の通知条件の既定値
+const (
+	DefaultMinLevel      = 4   // 大湧き
+	DefaultJumpThreshold = 0.3 // 予測値の上昇幅
+)
+
+// 通知本文に含める地点の上限
+const maxNotifySpots = 3
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// trigger は通知する理由
+type trigger int
+
+const (
+	triggerNone  trigger = iota
+	triggerLevel         // 設定したレベルに達した
+	triggerJump          // 予測値が大きく上がった
+)
+
+// Notifier は予測データの更新を受けて、条件に合う購読者にWeb Push通知を送る
+type Notifier struct {
+	store   *Store
+	sender  *Sender
+	cache   *cache.CacheManager
+	night   night.Config
+	weights model.TimelineFactors // 時間ごとの評価の重み（爆湧き指数の設定から取る）
+	wind    night.WindSpeeds      // 時間ごとの風の評価の閾値（爆湧き指数の設定から取る）
+	season  level.Season
+	logger  *slog.Logger
+	now     func() time.Time
+
+	mu sync.Mutex
+	wg sync.WaitGroup
+}
+
+// NewNotifier は新しいNotifierを初期化する
+func NewNotifier(store *Store, sender *Sender, cache *cache.CacheManager, nightConfig night.Config, timelineWeights model.TimelineFactors, timelineWind night.WindSpeeds, season level.Season, logger *slog.Logger) *Notifier {
+	return &Notifier{
+		store:   store,
+		sender:  sender,
+		cache:   cache,
+		night:   nightConfig,
+		weights: timelineWeights,
+		wind:    timelineWind,
+		season:  season,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// OnPrediction は予測データの更新をバックグラウンドで処理する
+// キャッシュの更新を待たせないよう、CacheManagerのPredictionListenerとして登録して使う
+func (n *Notifier) OnPrediction(update cache.PredictionUpdate) {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.process(update)
+	}()
+}
+
+// Wait は送信中の通知が終わるまで待つ
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+type predictionDay struct {
+	Date            string  `json:"date"`
+	PredictedAmount float64 `json:"predicted_amount"`
+}
+
+func (n *Notifier) process(update cache.PredictionUpdate) {
+	var days []predictionDay
+	if err := json.Unmarshal(update.Data, &days); err != nil {
+		n.logger.Error("通知用の予測データの解析エラー", "error", err)
+		return
+	}
+	// 前回の更新時の予測値は差分から取る（予測が変わっていない日は急上昇ではないので不要）
+	previous := map[string]float64{}
+	if update.Diff != nil {
+		for _, d := range update.Diff.Days {
+			if d.PreviousAmount != nil {
+				previous[d.Date] = *d.PreviousAmount
+			}
+		}
+	}
+
+	today := n.now().In(jst).Format("2006-01-02")
+	targets, err := n.store.ListTargets(today)
+	if err != nil {
+		n.logger.Error("通知対象の購読取得エラー", "error", err)
+		return
+	}
+	sent := 0
+	for _, target := range targets {
+		sub := target.PushSubscription
+		var hits []hit
+		for _, d := range days {
+			date, err := time.ParseInLocation("2006-01-02", d.Date, jst)
+			if err != nil || d.Date < today || !n.season.Contains(date) {
+				continue
+			}
+			var last *float64
+			if a, ok := target.LastNotified[d.Date]; ok {
+				last = &a
+			}
+			var prev *float64
+			if p, ok := previous[d.Date]; ok {
+				prev = &p
+			}
+			if t := evaluate(sub.MinLevel, sub.JumpThreshold, d.PredictedAmount, prev, last); t != triggerNone {
+				hits = append(hits, hit{day: d, trigger: t, previous: prev})
+			}
+		}
+		if len(hits) == 0 {
+			continue
+		}
+
+		payload, err := json.Marshal(n.buildMessage(sub.Spots, hits))
+		if err != nil {
+			n.logger.Error("通知本文の作成エラー", "error", err)
+			continue
+		}
+		err = n.sender.Send(sub, payload)
+		if errors.Is(err, ErrSubscriptionGone) {
+			n.logger.Info("無効になった購読を削除します", "device_id", sub.DeviceID)
+			if _, err := n.store.Delete(sub.DeviceID); err != nil {
+				n.logger.Error("購読の削除エラー", "device_id", sub.DeviceID, "error", err)
+			}
+			continue
+		}
+		if err != nil {
+			n.logger.Error("Web Push通知の送信エラー", "device_id", sub.DeviceID, "error", err)
+			continue
+		}
+		for _, h := range hits {
+			if err := n.store.MarkNotified(sub.DeviceID, h.day.Date, h.day.PredictedAmount); err != nil {
+				n.logger.Error("通知履歴の保存エラー", "device_id", sub.DeviceID, "error", err)
+			}
+		}
+		sent++
+	}
+	if sent > 0 {
+		n.logger.Info("Web Push通知を送信しました", "count", sent, "hash", update.Hash)
+	}
+}
+
+// evaluate は1日分の予測について通知するかどうかを判定する
+// previousは前回の更新時の予測値、lastNotifiedは前回通知したときの予測値（どちらもない場合はnil）
+func evaluate(minLevel int, jumpThreshold, amount float64, previous, lastNotified *float64) trigger {
+	lvl := level.FromAmount(amount)
+	if lastNotified != nil {
+		// 通知済みの日は、設定したレベルに初めて達したときか、通知時からさらに大きく上がったときだけ通知する
+		if lvl >= minLevel && level.FromAmount(*lastNotified) < minLevel {
+			return triggerLevel
+		}
+		if amount-*lastNotified >= jumpThreshold && lvl >= minLevel-1 {
+			return triggerJump
+		}
+		return triggerNone
+	}
+	if lvl >= minLevel {
+		return triggerLevel
+	}
+	// 設定したレベルの1つ手前までの急上昇も知らせる
+	if previous != nil && amount-*previous >= jumpThreshold && lvl >= minLevel-1 && lvl > 0 {
+		return triggerJump
+	}
+	return triggerNone
+}
+
+type hit struct {
+	day      predictionDay
+	trigger  trigger
+	previous *float64
+}
+
+// Message はService Workerに渡す通知の内容
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	Tag   string `json:"tag"`
+}
+
+// buildMessage はレベルの高い日を見出しにして通知の本文を作る
+// 見出しの日については、希望する地点のおすすめの時間帯も添える
+func (n *Notifier) buildMessage(spotIDs []string, hits []hit) Message {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].day.PredictedAmount > hits[j].day.PredictedAmount
+	})
+	top := hits[0]
+	lvl := level.FromAmount(top.day.PredictedAmount)
+
+	msg := Message{
+		URL: "/detail/" + top.day.Date,
+		Tag: "prediction-" + top.day.Date,
+	}
+	if top.trigger == triggerJump && top.previous != nil && level.FromAmount(*top.previous) < lvl {
+		msg.Title = fmt.Sprintf("%sの予測が%sに上がりました", level.FormatDate(top.day.Date), level.Name(lvl))
+	} else {
+		msg.Title = fmt.Sprintf("%sは%s予報です", level.FormatDate(top.day.Date), level.Name(lvl))
+	}
+
+	lines := []string{fmt.Sprintf("予測値 %.2f", top.day.PredictedAmount)}
+	if len(spotIDs) == 0 {
+		spotIDs = []string{spot.DefaultID}
+	}
+	for i, id := range spotIDs {
+		if i >= maxNotifySpots {
+			break
+		}
+		s, ok := spot.Get(id)
+		if !ok {
+			continue
+		}
+		if i == 0 && id != spot.DefaultID {
+			msg.URL += "?spot=" + id
+		}
+		if line := n.spotLine(s, top.day.Date); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for _, h := range hits[1:] {
+		lines = append(lines, fmt.Sprintf("%s %s（%.2f）", level.FormatDate(h.day.Date), level.Name(level.FromAmount(h.day.PredictedAmount)), h.day.PredictedAmount))
+	}
+	msg.Body = strings.Join(lines, "\n")
+	return msg
+}
+
+// spotLine は地点のおすすめの時間帯を1行にまとめる（詳細データがない場合は空文字）
+func (n *Notifier) spotLine(s spot.Spot, date string) string {
+	detail, ok := n.cache.GetDetail(s.ID, date)
+	if !ok {
+		return ""
+	}
+	timeline, err := night.BuildTimeline(n.night, n.weights, n.wind, detail, s.ShoreBearing)
+	if err != nil {
+		n.logger.Warn("通知用のタイムライン作成エラー", "spot", s.ID, "date", date, "error", err)
+		return ""
+	}
+	return s.Name + ": " + timeline.Summary
+}
@@ -0,0 +1,269 @@
+// backend/internal/push/push_test.go
+package push
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// pushService はプッシュサービスの代わりに通知を受け取り、ブラウザ側の鍵で復号する
+type pushService struct {
+	t          *testing.T
+	uaKey      *ecdh.PrivateKey
+	authSecret []byte
+	status     int
+	received   [][]byte
+	claims     jwt.MapClaims
+}
+
+func newPushService(t *testing.T) *pushService {
+	uaKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+	return &pushService{t: t, uaKey: uaKey, authSecret: authSecret, status: http.StatusCreated}
+}
+
+func (p *pushService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.status != http.StatusCreated {
+		w.WriteHeader(p.status)
+		return
+	}
+	if got := r.Header.Get("Content-Encoding"); got != "aes128gcm" {
+		p.t.Errorf("Content-Encoding = %q", got)
+	}
+	if r.Header.Get("TTL") == "" {
+		p.t.Error("TTLヘッダーがありません")
+	}
+	p.claims = p.verifyVAPID(r.Header.Get("Authorization"))
+	body, _ := io.ReadAll(r.Body)
+	p.received = append(p.received, p.decrypt(body))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (p *pushService) verifyVAPID(header string) jwt.MapClaims {
+	parts := strings.SplitN(strings.TrimPrefix(header, "vapid "), ", ", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "t=") || !strings.HasPrefix(parts[1], "k=") {
+		p.t.Fatalf("Authorizationヘッダーの形式が不正です: %q", header)
+	}
+	pub, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(parts[1], "k="))
+	if err != nil || len(pub) != 65 {
+		p.t.Fatalf("公開鍵の形式が不正です: %v", err)
+	}
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(pub[1:33]), Y: new(big.Int).SetBytes(pub[33:])}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(strings.TrimPrefix(parts[0], "t="), claims, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		p.t.Fatalf("VAPIDトークンの検証に失敗しました: %v", err)
+	}
+	return claims
+}
+
+func (p *pushService) decrypt(body []byte) []byte {
+	if len(body) < 21 {
+		p.t.Fatalf("本文が短すぎます: %d", len(body))
+	}
+	salt, idLen := body[:16], int(body[20])
+	asPublic, ciphertext := body[21:21+idLen], body[21+idLen:]
+	asKey, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	secret, err := p.uaKey.ECDH(asKey)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	cek, nonce, err := deriveKeys(secret, p.authSecret, salt, p.uaKey.PublicKey().Bytes(), asPublic)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		p.t.Fatalf("復号に失敗しました: %v", err)
+	}
+	if len(plain) == 0 || plain[len(plain)-1] != 0x02 {
+		p.t.Fatalf("最後のレコードの区切りがありません")
+	}
+	return plain[:len(plain)-1]
+}
+
+func (p *pushService) subscription(endpoint string) model.PushSubscription {
+	return model.PushSubscription{
+		DeviceID: "device-1",
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(p.uaKey.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(p.authSecret),
+	}
+}
+
+func testKeys(t *testing.T) *Keys {
+	priv, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := LoadKeys(
+		base64.RawURLEncoding.EncodeToString(priv.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(priv.Bytes()),
+		"mailto:admin@example.com",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestSend(t *testing.T) {
+	service := newPushService(t)
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	sender := NewSender(testKeys(t))
+	payload := []byte(`{"title":"4/12(日)は爆湧き予報です"}`)
+	if err := sender.Send(service.subscription(server.URL+"/push/abc"), payload); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(service.received) != 1 || !bytes.Equal(service.received[0], payload) {
+		t.Fatalf("受信した通知 = %q, want %q", service.received, payload)
+	}
+	if aud := service.claims["aud"]; aud != server.URL {
+		t.Errorf("aud = %v, want %s", aud, server.URL)
+	}
+	if sub := service.claims["sub"]; sub != "mailto:admin@example.com" {
+		t.Errorf("sub = %v", sub)
+	}
+}
+
+func TestSendGone(t *testing.T) {
+	service := newPushService(t)
+	service.status = http.StatusGone
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	err := NewSender(testKeys(t)).Send(service.subscription(server.URL), []byte(`{}`))
+	if !errors.Is(err, ErrSubscriptionGone) {
+		t.Fatalf("err = %v, want ErrSubscriptionGone", err)
+	}
+}
+
+func TestLoadKeysMismatch(t *testing.T) {
+	a, b := testKeys(t), testKeys(t)
+	priv := base64.RawURLEncoding.EncodeToString(a.private.D.FillBytes(make([]byte, 32)))
+	if _, err := LoadKeys(b.PublicKey(), priv, "mailto:admin@example.com"); err == nil {
+		t.Fatal("公開鍵と秘密鍵が一致しない場合はエラーになるべき")
+	}
+	if _, err := LoadKeys(a.PublicKey(), priv, "admin@example.com"); err == nil {
+		t.Fatal("subjectがmailto:でもhttps:でもない場合はエラーになるべき")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name               string
+		amount             float64
+		previous, notified *float64
+		want               trigger
+	}{
+		{"初めて大湧き以上", 1.2, nil, nil, triggerLevel},
+		{"湧き止まり", 1.0, f(0.95), nil, triggerNone},
+		{"湧きまで急上昇", 1.0, f(0.6), nil, triggerJump},
+		{"チョイ湧きへの急上昇は対象外", 0.7, f(0.3), nil, triggerNone},
+		{"通知済みで変化なし", 1.25, f(1.2), f(1.2), triggerNone},
+		{"通知済みからさらに上昇", 1.55, f(1.2), f(1.2), triggerJump},
+		{"急上昇の通知後に大湧きに達した", 1.16, f(1.0), f(1.0), triggerLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluate(DefaultMinLevel, DefaultJumpThreshold, tt.amount, tt.previous, tt.notified); got != tt.want {
+				t.Errorf("evaluate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	valid := []string{
+		"https://fcm.googleapis.com/fcm/send/abc",
+		"https://updates.push.services.mozilla.com/wpush/v2/abc",
+		"https://web.push.apple.com/abc",
+		"https://wns2-par02p.notify.windows.com/w/?token=abc",
+	}
+	for _, endpoint := range valid {
+		if err := ValidateEndpoint(endpoint); err != nil {
+			t.Errorf("ValidateEndpoint(%q) = %v", endpoint, err)
+		}
+	}
+	invalid := []string{
+		"http://fcm.googleapis.com/fcm/send/abc",
+		"https://127.0.0.1/push",
+		"https://169.254.169.254/latest/meta-data",
+		"https://localhost/push",
+		"https://fcm.googleapis.com.example.com/push",
+		"https://evilfcm.googleapis.com.attacker.test/push",
+		"https://fcm.googleapis.com:8443/fcm/send/abc",
+		"https://user@fcm.googleapis.com/fcm/send/abc",
+	}
+	for _, endpoint := range invalid {
+		if err := ValidateEndpoint(endpoint); err == nil {
+			t.Errorf("ValidateEndpoint(%q) は失敗するべきです", endpoint)
+		}
+	}
+}
+
+// TestListTargets は通知履歴を購読一覧と同じクエリで読み込むことをテストする
+func TestListTargets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	columns := []string{"device_id", "endpoint", "p256dh", "auth", "min_level", "jump_threshold", "spots", "created_at", "updated_at", "notified"}
+	mock.ExpectQuery("SELECT .+ FROM push_subscriptions s LEFT JOIN push_notifications n").
+		WithArgs("2026-04-10").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("device-a", "https://fcm.googleapis.com/fcm/send/a", "p", "a", 4, 0.3, "{}", now, now, `{"2026-04-11": 1.2}`).
+			AddRow("device-b", "https://fcm.googleapis.com/fcm/send/b", "p", "a", 5, 0.3, "{toyama}", now, now, `{}`))
+
+	targets, err := NewStore(db).ListTargets("2026-04-10")
+	if err != nil {
+		t.Fatalf("ListTargets: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("len(targets) = %d, want 2", len(targets))
+	}
+	if a, ok := targets[0].LastNotified["2026-04-11"]; !ok || a != 1.2 {
+		t.Errorf("device-aの通知履歴 = %v", targets[0].LastNotified)
+	}
+	if len(targets[1].LastNotified) != 0 || len(targets[1].Spots) != 1 {
+		t.Errorf("device-b = %+v", targets[1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
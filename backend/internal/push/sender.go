@@ -0,0 +1,110 @@
+// backend/internal/push/sender.go
+package push
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// 通知をプッシュサービスに保持してもらう時間（端末がオフラインの場合）
+const messageTTL = 12 * time.Hour
+
+// ErrSubscriptionGone はプッシュサービスから購読が無効になったと返されたことを示す
+var ErrSubscriptionGone = errors.New("購読は無効になっています")
+
+// 購読のエンドポイントとして受け付けるプッシュサービスのホスト（サブドメインを含む）
+// 任意のURLを登録できると、通知の送信を使ってサーバーから内部のアドレスにリクエストを送らせられるため
+var pushServiceHosts = []string{
+	"fcm.googleapis.com",        // Chrome・Edge
+	"push.services.mozilla.com", // Firefox
+	"push.apple.com",            // Safari
+	"notify.windows.com",        // Windowsの旧Edge
+}
+
+// ValidateEndpoint は購読のエンドポイントが既知のプッシュサービスのhttpsのURLかどうかを確認する
+func ValidateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("エンドポイントのURLが不正です: %w", err)
+	}
+	if u.Scheme != "https" || u.User != nil || (u.Port() != "" && u.Port() != "443") {
+		return fmt.Errorf("エンドポイントはhttpsのURLで指定してください: %s", endpoint)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range pushServiceHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return nil
+		}
+	}
+	return fmt.Errorf("既知のプッシュサービスのエンドポイントではありません: %s", host)
+}
+
+// Sender は暗号化した通知をプッシュサービスに送る
+type Sender struct {
+	keys   *Keys
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender は新しいSenderを初期化する
+func NewSender(keys *Keys) *Sender {
+	return &Sender{
+		keys:   keys,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// Send は1件の購読に通知を送る
+// 購読が期限切れ・解除済みの場合はErrSubscriptionGoneを返す
+func (s *Sender) Send(sub model.PushSubscription, payload []byte) error {
+	uaPublic, err := decodeBase64(sub.P256dh)
+	if err != nil {
+		return fmt.Errorf("購読の公開鍵の形式が不正です: %w", err)
+	}
+	authSecret, err := decodeBase64(sub.Auth)
+	if err != nil {
+		return fmt.Errorf("購読の認証シークレットの形式が不正です: %w", err)
+	}
+	body, err := encrypt(payload, uaPublic, authSecret)
+	if err != nil {
+		return err
+	}
+	authorization, err := s.keys.authorization(sub.Endpoint, s.now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("リクエストの作成に失敗しました: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(messageTTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("プッシュサービスへの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("プッシュサービスがエラーを返しました (status %d): %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
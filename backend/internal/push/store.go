@@ -0,0 +1,145 @@
+// backend/internal/push/store.go
+package push
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// Store はWeb Push通知の購読情報をPostgresに保存・参照する
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore は新しいStoreを初期化する
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Save はデバイスの購読情報を登録または更新する
+// 同じエンドポイントが別のデバイスIDで登録されていた場合は置き換える
+func (s *Store) Save(sub model.PushSubscription) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクション開始失敗: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM push_subscriptions WHERE endpoint = $1 AND device_id <> $2`, sub.Endpoint, sub.DeviceID); err != nil {
+		return fmt.Errorf("購読の重複削除失敗: %w", err)
+	}
+	query := `INSERT INTO push_subscriptions (device_id, endpoint, p256dh, auth, min_level, jump_threshold, spots)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (device_id) DO UPDATE SET
+			endpoint = EXCLUDED.endpoint,
+			p256dh = EXCLUDED.p256dh,
+			auth = EXCLUDED.auth,
+			min_level = EXCLUDED.min_level,
+			jump_threshold = EXCLUDED.jump_threshold,
+			spots = EXCLUDED.spots,
+			updated_at = CURRENT_TIMESTAMP`
+	if _, err := tx.Exec(query, sub.DeviceID, sub.Endpoint, sub.P256dh, sub.Auth, sub.MinLevel, sub.JumpThreshold, pq.Array(sub.Spots)); err != nil {
+		return fmt.Errorf("購読の保存失敗: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Get はデバイスの購読情報を返す（未登録の場合はnil）
+func (s *Store) Get(deviceID string) (*model.PushSubscription, error) {
+	row := s.db.QueryRow(`SELECT device_id, endpoint, p256dh, auth, min_level, jump_threshold, spots, created_at, updated_at
+		FROM push_subscriptions WHERE device_id = $1`, deviceID)
+	sub, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("購読の取得失敗: %w", err)
+	}
+	return sub, nil
+}
+
+// Delete はデバイスの購読を解除する。解除した場合はtrueを返す
+func (s *Store) Delete(deviceID string) (bool, error) {
+	result, err := s.db.Exec(`DELETE FROM push_subscriptions WHERE device_id = $1`, deviceID)
+	if err != nil {
+		return false, fmt.Errorf("購読の削除失敗: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Target は通知対象の購読と、日付ごとに最後に通知した予測値
+type Target struct {
+	model.PushSubscription
+	LastNotified map[string]float64
+}
+
+// ListTargets は全ての購読を、from（2006-01-02）以降の日付について最後に通知した予測値と合わせて返す
+// 購読ごとに通知履歴を問い合わせないよう、1回のクエリでまとめて取得する
+func (s *Store) ListTargets(from string) ([]Target, error) {
+	rows, err := s.db.Query(`SELECT s.device_id, s.endpoint, s.p256dh, s.auth, s.min_level, s.jump_threshold, s.spots, s.created_at, s.updated_at,
+			COALESCE(json_object_agg(n.target_date, n.predicted_amount) FILTER (WHERE n.target_date IS NOT NULL), '{}')
+		FROM push_subscriptions s
+		LEFT JOIN push_notifications n ON n.device_id = s.device_id AND n.target_date >= $1
+		GROUP BY s.device_id
+		ORDER BY s.created_at`, from)
+	if err != nil {
+		return nil, fmt.Errorf("購読一覧の取得失敗: %w", err)
+	}
+	defer rows.Close()
+
+	targets := []Target{}
+	for rows.Next() {
+		var notified []byte
+		sub, err := scanSubscription(rows, &notified)
+		if err != nil {
+			return nil, fmt.Errorf("購読行のスキャン失敗: %w", err)
+		}
+		target := Target{PushSubscription: *sub}
+		if err := json.Unmarshal(notified, &target.LastNotified); err != nil {
+			return nil, fmt.Errorf("通知履歴の解析失敗: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, rows.Err()
+}
+
+// MarkNotified はデバイスに指定日の通知を送ったことを記録する
+func (s *Store) MarkNotified(deviceID, date string, amount float64) error {
+	query := `INSERT INTO push_notifications (device_id, target_date, predicted_amount)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (device_id, target_date) DO UPDATE SET
+			predicted_amount = EXCLUDED.predicted_amount,
+			sent_at = CURRENT_TIMESTAMP`
+	if _, err := s.db.Exec(query, deviceID, date, amount); err != nil {
+		return fmt.Errorf("通知履歴の保存失敗: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSubscription は購読の列を読み込む。extraには購読の列の後に続く列の読み込み先を渡す
+func scanSubscription(row rowScanner, extra ...interface{}) (*model.PushSubscription, error) {
+	var sub model.PushSubscription
+	var spots pq.StringArray
+	dest := append([]interface{}{&sub.DeviceID, &sub.Endpoint, &sub.P256dh, &sub.Auth, &sub.MinLevel, &sub.JumpThreshold, &spots, &sub.CreatedAt, &sub.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	sub.Spots = []string(spots)
+	if sub.Spots == nil {
+		sub.Spots = []string{}
+	}
+	return &sub, nil
+}
@@ -0,0 +1,98 @@
+// backend/internal/push/vapid.go
+package push
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// VAPIDトークンの有効期限（仕様上の上限は24時間）
+const vapidTokenTTL = 12 * time.Hour
+
+// Keys はアプリケーションサーバーを識別するVAPID鍵（P-256）
+type Keys struct {
+	private *ecdsa.PrivateKey
+	public  []byte // 非圧縮形式（65バイト）
+	subject string // 連絡先（mailto: または https:）
+}
+
+// LoadKeys はbase64urlでエンコードされたVAPID鍵を読み込む
+//
+//	publicKey   公開鍵（base64url、非圧縮形式65バイト）
+//	privateKey  秘密鍵（base64url、32バイト）
+//	subject     連絡先（例: mailto:admin@example.com）
+//
+// 鍵は npx web-push generate-vapid-keys などで生成できる
+func LoadKeys(publicKey, privateKey, subject string) (*Keys, error) {
+	d, err := decodeBase64(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID秘密鍵の形式が不正です: %w", err)
+	}
+	ecdhKey, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID秘密鍵の形式が不正です: %w", err)
+	}
+	pub := ecdhKey.PublicKey().Bytes()
+	if publicKey != "" {
+		given, err := decodeBase64(publicKey)
+		if err != nil || string(given) != string(pub) {
+			return nil, fmt.Errorf("VAPID公開鍵が秘密鍵と一致しません")
+		}
+	}
+	if u, err := url.Parse(subject); err != nil || (u.Scheme != "mailto" && u.Scheme != "https") {
+		return nil, fmt.Errorf("VAPID_SUBJECTはmailto:またはhttps:で指定してください: %q", subject)
+	}
+
+	// golang-jwtで署名するためecdsaの鍵に変換する
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}
+	return &Keys{private: key, public: pub, subject: subject}, nil
+}
+
+// PublicKey はブラウザのPushManager.subscribeに渡す公開鍵（base64url）を返す
+func (k *Keys) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(k.public)
+}
+
+// authorization はプッシュサービスに送るAuthorizationヘッダーの値を作る（RFC 8292）
+func (k *Keys) authorization(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("エンドポイントの形式が不正です: %w", err)
+	}
+	claims := jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidTokenTTL).Unix(),
+		"sub": k.subject,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(k.private)
+	if err != nil {
+		return "", fmt.Errorf("VAPIDトークンの署名に失敗しました: %w", err)
+	}
+	return "vapid t=" + token + ", k=" + k.PublicKey(), nil
+}
+
+// decodeBase64 はパディングの有無やURL用・標準のどちらの形式でも受け付ける
+func decodeBase64(s string) ([]byte, error) {
+	if b, err := base64.RawURLEncoding.DecodeString(s); err == nil {
+		return b, nil
+	}
+	if b, err := base64.URLEncoding.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
@@ -0,0 +1,245 @@
+// backend/internal/scheduler/scheduler.go
+package scheduler
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// Config はキャッシュ更新の間隔設定
+type Config struct {
+	Interval       time.Duration // シーズン外の更新間隔
+	SeasonInterval time.Duration // シーズン中の更新間隔
+	Season         level.Season
+}
+
+// DefaultConfig は既定の更新間隔（シーズン中は1時間ごと、それ以外は6時間ごと）
+func DefaultConfig() Config {
+	return Config{
+		Interval:       6 * time.Hour,
+		SeasonInterval: time.Hour,
+		Season:         level.Season{StartMonth: time.March, EndMonth: time.May},
+	}
+}
+
+// IntervalAt は指定時刻における更新間隔を返す
+func (c Config) IntervalAt(t time.Time) time.Duration {
+	if c.Season.Contains(t.In(jst)) {
+		return c.SeasonInterval
+	}
+	return c.Interval
+}
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// Scheduler は予測データと詳細データの定期更新を行う
+// 更新は常に1つだけ実行され、実行中に要求された更新はスキップされる
+type Scheduler struct {
+	logger *slog.Logger
+	cache  *cache.CacheManager
+	config Config
+
+	mu            sync.Mutex
+	running       bool
+	stopped       bool
+	runningSince  time.Time
+	lastRun       *model.RefreshRun
+	lastSuccessAt *time.Time
+	nextRunAt     *time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New は新しいSchedulerを初期化する
+func New(logger *slog.Logger, cache *cache.CacheManager, config Config) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		cache:  cache,
+		config: config,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start は起動時の初回更新を行い、定期更新のゴルーチンを起動する
+func (s *Scheduler) Start() {
+	s.Trigger("startup")
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// Stop は定期更新を止め、実行中の更新が終わるまで待つ
+// Stop以降のTriggerは何もしない
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	s.mu.Unlock()
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+	for {
+		interval := s.config.IntervalAt(time.Now())
+		next := time.Now().Add(interval)
+		s.mu.Lock()
+		s.nextRunAt = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+			s.Trigger("schedule")
+		case <-s.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Trigger はバックグラウンドで更新を開始する
+// すでに更新が実行中の場合は何もせずfalseを返す
+func (s *Scheduler) Trigger(trigger string) bool {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		s.logger.Info("停止処理中のためキャッシュ更新をスキップします", "trigger", trigger)
+		return false
+	}
+	if s.running {
+		s.mu.Unlock()
+		s.logger.Info("キャッシュ更新が実行中のためスキップします", "trigger", trigger)
+		return false
+	}
+	s.running = true
+	s.runningSince = time.Now()
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		s.run(trigger)
+	}()
+	return true
+}
+
+// run は予測データと詳細データを並行して取得し、結果を記録する
+func (s *Scheduler) run(trigger string) {
+	started := time.Now()
+	s.logger.Info("キャッシュ更新を開始します", "trigger", trigger)
+
+	var predictionErr error
+	var detailResults []cache.DetailResult
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		predictionErr = s.cache.FetchAndCachePredictionData()
+	}()
+	go func() {
+		defer wg.Done()
+		detailResults = s.cache.FetchAndCacheDetailData()
+	}()
+	wg.Wait()
+
+	run := &model.RefreshRun{
+		Trigger:    trigger,
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Prediction: s.taskResult("", "", predictionErr, errFetchFailed),
+		Details:    make([]model.RefreshTaskResult, 0, len(detailResults)),
+	}
+	run.DurationMs = run.FinishedAt.Sub(started).Milliseconds()
+	if predictionErr == nil {
+		run.Succeeded++
+	} else {
+		run.Failed++
+	}
+	for _, r := range detailResults {
+		// 型付きの形式に変換できなかった日付はschema=2で返せないため失敗として記録する
+		err, category := r.Err, errFetchFailed
+		if err == nil {
+			err, category = r.TypedErr, errConvertFailed
+		}
+		run.Details = append(run.Details, s.taskResult(r.SpotID, r.Date, err, category))
+		if err == nil {
+			run.Succeeded++
+		} else {
+			run.Failed++
+		}
+	}
+	sort.Slice(run.Details, func(i, j int) bool {
+		if run.Details[i].Date != run.Details[j].Date {
+			return run.Details[i].Date < run.Details[j].Date
+		}
+		return run.Details[i].Spot < run.Details[j].Spot
+	})
+
+	s.mu.Lock()
+	s.running = false
+	s.lastRun = run
+	if run.Failed == 0 {
+		finished := run.FinishedAt
+		s.lastSuccessAt = &finished
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("キャッシュ更新が完了しました", "trigger", trigger, "duration_ms", run.DurationMs, "succeeded", run.Succeeded, "failed", run.Failed)
+
+	// 次回のコールドスタートに備えて、取得できたデータをスナップショットとして保存する
+	if run.Succeeded > 0 {
+		if err := s.cache.SaveSnapshot(); err != nil {
+			s.logger.Error("キャッシュのスナップショット保存エラー", "error", err)
+		}
+	}
+}
+
+// 状態APIは認証なしで公開されるため、エラーは分類だけを返す
+// 取得元のURLや応答の内容を含む元のエラーはログにだけ出す
+const (
+	errFetchFailed   = "fetch_failed"
+	errConvertFailed = "convert_failed"
+)
+
+func (s *Scheduler) taskResult(spotID, date string, err error, category string) model.RefreshTaskResult {
+	result := model.RefreshTaskResult{Spot: spotID, Date: date, OK: err == nil}
+	if err != nil {
+		result.Error = category
+		s.logger.Warn("キャッシュ更新の取得に失敗しました", "spot", spotID, "date", date, "category", category, "error", err)
+	}
+	return result
+}
+
+// Interval は現在の更新間隔を返す
+func (s *Scheduler) Interval() time.Duration {
+	return s.config.IntervalAt(time.Now())
+}
+
+// Status は更新ジョブの現在の状態を返す
+func (s *Scheduler) Status() model.RefreshStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := model.RefreshStatus{
+		Running:       s.running,
+		Interval:      s.config.IntervalAt(time.Now()).String(),
+		LastRun:       s.lastRun,
+		LastSuccessAt: s.lastSuccessAt,
+		NextRunAt:     s.nextRunAt,
+	}
+	if s.running {
+		since := s.runningSince
+		status.RunningSince = &since
+	}
+	return status
+}
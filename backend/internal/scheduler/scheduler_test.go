@@ -0,0 +1,144 @@
+// backend/internal/scheduler/scheduler_test.go
+package scheduler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/provider"
+)
+
+// TestIntervalAt はシーズン中とシーズン外で更新間隔が切り替わることをテストする
+func TestIntervalAt(t *testing.T) {
+	cfg := DefaultConfig()
+	cases := []struct {
+		month time.Month
+		want  time.Duration
+	}{
+		{time.February, 6 * time.Hour},
+		{time.March, time.Hour},
+		{time.May, time.Hour},
+		{time.June, 6 * time.Hour},
+	}
+	for _, c := range cases {
+		at := time.Date(2026, c.month, 15, 12, 0, 0, 0, jst)
+		if got := cfg.IntervalAt(at); got != c.want {
+			t.Errorf("%s: IntervalAt = %s, want %s", c.month, got, c.want)
+		}
+	}
+
+	// 年をまたぐシーズン設定
+	cfg.Season = level.Season{StartMonth: time.November, EndMonth: time.February}
+	if got := cfg.IntervalAt(time.Date(2026, time.January, 10, 0, 0, 0, 0, jst)); got != time.Hour {
+		t.Errorf("年またぎのシーズン中の間隔 = %s, want 1h", got)
+	}
+}
+
+// TestTriggerAfterStop は停止後の更新要求が無視されることをテストする
+func TestTriggerAfterStop(t *testing.T) {
+	s := New(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, DefaultConfig())
+	s.Stop()
+	if s.Trigger("manual") {
+		t.Error("停止後にTriggerが更新を開始しました")
+	}
+	s.Stop() // 2回呼んでもpanicしないこと
+}
+
+// blockingPrediction は呼び出し回数を数え、releaseが閉じられるまで取得を止める予測データの取得元
+type blockingPrediction struct {
+	provider.PredictionProvider
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingPrediction) FetchPrediction() ([]byte, error) {
+	if b.calls.Add(1) == 1 {
+		close(b.started)
+	}
+	<-b.release
+	return b.PredictionProvider.FetchPrediction()
+}
+
+// TestTriggerSingleFlight は同時に要求された更新のうち1つだけが実行され、取得が1回だけ行われることをテストする
+func TestTriggerSingleFlight(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	providers := provider.NewFixtureSet("../../testdata/fixtures")
+	prediction := &blockingPrediction{
+		PredictionProvider: providers.Prediction,
+		started:            make(chan struct{}),
+		release:            make(chan struct{}),
+	}
+	providers.Prediction = prediction
+	s := New(logger, cache.NewCacheManagerWithOptions(logger, providers, cache.Options{}), DefaultConfig())
+
+	var started atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.Trigger("manual") {
+				started.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	select {
+	case <-prediction.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("予測データの取得が始まりません")
+	}
+	// 実行中の更新がある間の要求もスキップされる
+	if s.Trigger("manual") {
+		started.Add(1)
+	}
+	close(prediction.release)
+	s.Stop()
+
+	if got := started.Load(); got != 1 {
+		t.Errorf("開始された更新 = %d, want 1", got)
+	}
+	if got := prediction.calls.Load(); got != 1 {
+		t.Errorf("予測データの取得回数 = %d, want 1", got)
+	}
+	if status := s.Status(); status.LastRun == nil || !status.LastRun.Prediction.OK {
+		t.Errorf("更新結果が記録されていません: %+v", status.LastRun)
+	}
+}
+
+// failingPrediction は取得元のURLを含むエラーを返す予測データの取得元
+type failingPrediction struct {
+	provider.PredictionProvider
+}
+
+func (failingPrediction) FetchPrediction() ([]byte, error) {
+	return nil, errors.New("GET https://internal.example/prediction?token=secret: 500")
+}
+
+// TestStatusHidesErrorDetails は状態APIに元のエラーが含まれず、分類だけが返ることをテストする
+func TestStatusHidesErrorDetails(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	providers := provider.NewFixtureSet("../../testdata/fixtures")
+	providers.Prediction = failingPrediction{providers.Prediction}
+	s := New(logger, cache.NewCacheManagerWithOptions(logger, providers, cache.Options{}), DefaultConfig())
+	if !s.Trigger("manual") {
+		t.Fatal("更新が開始されません")
+	}
+	s.Stop()
+
+	status := s.Status()
+	if status.LastRun == nil {
+		t.Fatal("更新結果が記録されていません")
+	}
+	if got := status.LastRun.Prediction; got.OK || got.Error != errFetchFailed {
+		t.Errorf("予測データの結果 = %+v, want error %q", got, errFetchFailed)
+	}
+}
@@ -0,0 +1,132 @@
+// backend/internal/scoring/config.go
+package scoring
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+)
+
+//go:embed default_config.json
+var defaultConfigJSON []byte
+
+// Config は爆湧き指数の算出に使う重みと閾値
+type Config struct {
+	Weights Weights `json:"weights"`
+	// 指数からレベルを判定する閾値 [爆湧き, 大湧き, 湧き, チョイ湧き, プチ湧き]
+	LevelThresholds []float64 `json:"level_thresholds"`
+	// 予測値がこの値以上で予測の評価を満点とする（フロントエンドのWAKI_THRESHOLDS[0]と揃える）
+	PredictionFullScore float64    `json:"prediction_full_score"`
+	Wind                WindConfig `json:"wind"`
+	// 風・降水・潮を評価する夜の時間帯（NIGHT_WINDOWから設定し、指数設定ファイルでは指定しない）
+	Night night.Config `json:"-"`
+	// シーズン外の夜はレベルを付けない（FORECAST_SEASON_MONTHSから設定し、指数設定ファイルでは指定しない）
+	Season level.Season `json:"-"`
+}
+
+// Weights は要因ごとの重み（合計が1である必要はない）
+type Weights struct {
+	Prediction    float64 `json:"prediction"`
+	Moon          float64 `json:"moon"`
+	Tide          float64 `json:"tide"`
+	Wind          float64 `json:"wind"`
+	Precipitation float64 `json:"precipitation"`
+}
+
+// WindConfig は風の評価方法
+type WindConfig struct {
+	CalmSpeed float64 `json:"calm_speed"` // この風速（m/s）以下は満点
+	MaxSpeed  float64 `json:"max_speed"`  // この風速以上は0点
+	// 風向は地点ごとの浜の向き（spot.ShoreBearing）に対して評価するので設定しない
+}
+
+// DefaultConfig は組み込みの設定を返す
+func DefaultConfig() Config {
+	cfg, err := parseConfig(defaultConfigJSON, Config{Night: night.DefaultConfig(), Season: level.DefaultSeason()})
+	if err != nil {
+		panic(fmt.Sprintf("組み込みの指数設定が不正です: %v", err))
+	}
+	return cfg
+}
+
+// LoadConfig はJSONファイルから設定を読み込む
+// ファイルに書かれていない項目は組み込みの設定の値を使う
+func LoadConfig(path string) (Config, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("指数設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	return parseConfig(body, DefaultConfig())
+}
+
+// parseConfig はbaseの値にJSONの内容を上書きして検証する
+func parseConfig(body []byte, base Config) (Config, error) {
+	cfg := base
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("指数設定の形式が不正です: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate は設定値が正しいか検証する
+func (c Config) Validate() error {
+	w := c.Weights
+	for name, v := range map[string]float64{
+		"prediction": w.Prediction, "moon": w.Moon, "tide": w.Tide, "wind": w.Wind, "precipitation": w.Precipitation,
+	} {
+		if v < 0 {
+			return fmt.Errorf("重み%sが負の値です: %v", name, v)
+		}
+	}
+	if w.Prediction+w.Moon+w.Tide+w.Wind+w.Precipitation <= 0 {
+		return fmt.Errorf("重みの合計が0です")
+	}
+	if w.Moon+w.Tide+w.Wind+w.Precipitation <= 0 {
+		return fmt.Errorf("月・潮・風・降水の重みの合計が0です（時間ごとの評価に使います）")
+	}
+	if len(c.LevelThresholds) != 5 {
+		return fmt.Errorf("level_thresholdsは5個指定してください: %d個", len(c.LevelThresholds))
+	}
+	for i, t := range c.LevelThresholds {
+		if t <= 0 || t > 100 || (i > 0 && t >= c.LevelThresholds[i-1]) {
+			return fmt.Errorf("level_thresholdsは100以下の正の値を降順で指定してください: %v", c.LevelThresholds)
+		}
+	}
+	if c.PredictionFullScore <= 0 {
+		return fmt.Errorf("prediction_full_scoreは正の値を指定してください: %v", c.PredictionFullScore)
+	}
+	if err := c.Night.Validate(); err != nil {
+		return err
+	}
+	if c.Wind.CalmSpeed < 0 || c.Wind.MaxSpeed <= c.Wind.CalmSpeed {
+		return fmt.Errorf("風速の設定が不正です（0 <= calm_speed < max_speed）: %v, %v", c.Wind.CalmSpeed, c.Wind.MaxSpeed)
+	}
+	return nil
+}
+
+// TimelineWeights は夜の時間ごとの評価（night.BuildTimeline）に使う重みを返す
+// 予測値は日ごとの値なので除き、月齢の重みを暗さの重みとする
+func (c Config) TimelineWeights() model.TimelineFactors {
+	return model.TimelineFactors{
+		Tide:     c.Weights.Tide,
+		Wind:     c.Weights.Wind,
+		Rain:     c.Weights.Precipitation,
+		Darkness: c.Weights.Moon,
+	}
+}
+
+// TimelineWind は夜の時間ごとの評価（night.BuildTimeline）に使う風速の閾値を返す
+func (c Config) TimelineWind() night.WindSpeeds {
+	return night.WindSpeeds{Calm: c.Wind.CalmSpeed, Strong: c.Wind.MaxSpeed}
+}
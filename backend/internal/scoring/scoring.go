@@ -0,0 +1,263 @@
+// backend/internal/scoring/scoring.go
+package scoring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/astronomy"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// ErrNoPrediction は指定日の予測データがキャッシュにない場合のエラー
+var ErrNoPrediction = errors.New("指定された日付の予測データがありません")
+
+// 潮名ごとの評価（潮が大きく動く日ほど良いとされる）
+var tideNameScores = map[string]float64{
+	"大潮": 1.0,
+	"中潮": 0.8,
+	"小潮": 0.5,
+	"長潮": 0.4,
+	"若潮": 0.5,
+}
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// Calculator はキャッシュされた予測・詳細データから爆湧き指数を算出する
+type Calculator struct {
+	cache  *cache.CacheManager
+	config Config
+}
+
+// NewCalculator は新しいCalculatorを初期化する
+func NewCalculator(cache *cache.CacheManager, config Config) *Calculator {
+	return &Calculator{cache: cache, config: config}
+}
+
+// Config は指数の算出に使っている設定を返す
+func (c *Calculator) Config() Config {
+	return c.config
+}
+
+// predictionDay は予測APIのレスポンスのうち指数の算出に使う1日分のフィールド
+type predictionDay struct {
+	Date                        string   `json:"date"`
+	PredictedAmount             float64  `json:"predicted_amount"`
+	MoonAge                     *float64 `json:"moon_age"`
+	PrecipitationProbabilityMax *float64 `json:"precipitation_probability_max"`
+	DominantWindDirection       *float64 `json:"dominant_wind_direction"`
+}
+
+// Compute は指定地点・指定日の夜の爆湧き指数を算出する
+// 詳細データがまだない場合は予測データに含まれる値だけで算出する
+func (c *Calculator) Compute(spotID, dateStr string) (*model.BakuwakiIndex, error) {
+	s, ok := spot.Get(spotID)
+	if !ok {
+		return nil, fmt.Errorf("地点が存在しません: %s", spotID)
+	}
+	entry, ok := c.cache.GetPredictionEntry()
+	if !ok {
+		return nil, ErrNoPrediction
+	}
+	var days []predictionDay
+	if err := json.Unmarshal(entry.Data, &days); err != nil {
+		return nil, fmt.Errorf("予測データの形式が不正です: %w", err)
+	}
+	var day *predictionDay
+	for i := range days {
+		if days[i].Date == dateStr {
+			day = &days[i]
+			break
+		}
+	}
+	if day == nil {
+		return nil, ErrNoPrediction
+	}
+	date, err := time.ParseInLocation("2006-01-02", dateStr, jst)
+	if err != nil {
+		return nil, fmt.Errorf("日付の形式が不正です: %w", err)
+	}
+
+	detail, _ := c.cache.GetDetail(spotID, dateStr)
+	index := score(c.config, date, day, detail, s.ShoreBearing)
+	index.SpotID = spotID
+	index.PredictionFetchedAt = entry.FetchedAt
+	if detail != nil {
+		index.DetailFetchedAt = &detail.FetchedAt
+	}
+	return index, nil
+}
+
+// score は要因ごとの評価を重み付きで合計し、0〜100の指数にする
+// データのない要因は除外し、残りの重みで按分する。shoreBearingは浜から海を向いた方角（度）
+func score(cfg Config, date time.Time, day *predictionDay, detail *model.DetailData, shoreBearing float64) *model.BakuwakiIndex {
+	nightStart, nightEnd := cfg.Night.Window(date)
+
+	factors := []model.IndexFactor{
+		predictionFactor(cfg, day),
+		moonFactor(cfg, date, day, detail),
+		tideFactor(cfg, detail, nightStart, nightEnd),
+		windFactor(cfg, day, detail, nightStart, nightEnd, shoreBearing),
+		precipitationFactor(cfg, day, detail, nightStart, nightEnd),
+	}
+	var totalWeight, weighted float64
+	for _, f := range factors {
+		if f.Score != nil {
+			totalWeight += f.Weight
+			weighted += f.Weight * *f.Score
+		}
+	}
+	result := &model.BakuwakiIndex{
+		Date:     date.Format("2006-01-02"),
+		InSeason: cfg.Season.Contains(date),
+		Factors:  factors,
+	}
+	if totalWeight > 0 {
+		for i := range factors {
+			if factors[i].Score != nil {
+				factors[i].Contribution = round1(100 * factors[i].Weight * *factors[i].Score / totalWeight)
+			}
+		}
+		result.Index = int(math.Round(100 * weighted / totalWeight))
+	}
+	result.Level = -1
+	if result.InSeason {
+		result.Level = indexLevel(cfg.LevelThresholds, result.Index)
+	}
+	return result
+}
+
+// indexLevel は指数から0〜5のレベルを判定する
+func indexLevel(thresholds []float64, index int) int {
+	for i, t := range thresholds {
+		if float64(index) >= t {
+			return len(thresholds) - i
+		}
+	}
+	return 0
+}
+
+func predictionFactor(cfg Config, day *predictionDay) model.IndexFactor {
+	s := math.Min(math.Max(day.PredictedAmount/cfg.PredictionFullScore, 0), 1)
+	return factor("prediction", cfg.Weights.Prediction, &day.PredictedAmount, &s, "")
+}
+
+// moonFactor は新月に近いほど高く評価する（月齢0で1、満月で0）
+func moonFactor(cfg Config, date time.Time, day *predictionDay, detail *model.DetailData) model.IndexFactor {
+	age := day.MoonAge
+	if detail != nil && detail.Tide.Moon.Age != nil {
+		age = detail.Tide.Moon.Age
+	}
+	note := ""
+	if age == nil {
+		computed := math.Round(astronomy.MoonAge(time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, jst))*10) / 10
+		age, note = &computed, "月齢は計算値"
+	}
+	s := (1 + math.Cos(2*math.Pi**age/astronomy.SynodicMonth)) / 2
+	return factor("moon", cfg.Weights.Moon, age, &s, note)
+}
+
+// tideFactor は潮名と、夜の時間帯に満潮があるかどうかで評価する
+func tideFactor(cfg Config, detail *model.DetailData, nightStart, nightEnd time.Time) model.IndexFactor {
+	if detail == nil {
+		return factor("tide", cfg.Weights.Tide, nil, nil, "潮汐データなし")
+	}
+	nameScore, ok := tideNameScores[detail.Tide.TideName]
+	if !ok {
+		nameScore = 0.5
+	}
+	events := detail.Tide.Events
+	if detail.NextTide != nil {
+		events = append(append([]model.TideEvent{}, events...), detail.NextTide.Events...)
+	}
+	floodScore, note := 0.0, "夜間に満潮なし"
+	for _, e := range events {
+		if e.Type == model.TideFlood && !e.Time.Before(nightStart) && !e.Time.After(nightEnd) {
+			floodScore, note = 1.0, "夜間の満潮 "+e.Time.Format("15:04")
+			break
+		}
+	}
+	s := 0.5*nameScore + 0.5*floodScore
+	return factor("tide", cfg.Weights.Tide, nil, &s, detail.Tide.TideName+"、"+note)
+}
+
+// windFactor は夜間の平均風速と、浜の向きに対する風向で評価する（時間ごとの評価のnight.ShoreWindFactorと同じ）
+// 詳細データがない場合は予測データの主風向のみで評価する
+func windFactor(cfg Config, day *predictionDay, detail *model.DetailData, nightStart, nightEnd time.Time, shoreBearing float64) model.IndexFactor {
+	hours := nightHours(detail, nightStart, nightEnd)
+	if len(hours) == 0 {
+		if day.DominantWindDirection == nil {
+			return factor("wind", cfg.Weights.Wind, nil, nil, "風のデータなし")
+		}
+		s := night.ShoreWindFactor(*day.DominantWindDirection, shoreBearing)
+		return factor("wind", cfg.Weights.Wind, nil, &s, "風速データなし（主風向のみで評価）")
+	}
+	var speedSum, x, y float64
+	for _, h := range hours {
+		speedSum += h.WindSpeed
+		rad := h.WindDirection * math.Pi / 180
+		x += h.WindSpeed * math.Sin(rad)
+		y += h.WindSpeed * math.Cos(rad)
+	}
+	avgSpeed := speedSum / float64(len(hours))
+	speedScore := math.Min(math.Max((cfg.Wind.MaxSpeed-avgSpeed)/(cfg.Wind.MaxSpeed-cfg.Wind.CalmSpeed), 0), 1)
+	s := speedScore
+	if avgSpeed > cfg.Wind.CalmSpeed && (x != 0 || y != 0) {
+		direction := math.Mod(math.Atan2(x, y)*180/math.Pi+360, 360)
+		s = speedScore * night.ShoreWindFactor(direction, shoreBearing)
+	}
+	avgSpeed = round1(avgSpeed)
+	return factor("wind", cfg.Weights.Wind, &avgSpeed, &s, "")
+}
+
+// precipitationFactor は夜間の最大降水確率が低いほど高く評価する
+func precipitationFactor(cfg Config, day *predictionDay, detail *model.DetailData, nightStart, nightEnd time.Time) model.IndexFactor {
+	hours := nightHours(detail, nightStart, nightEnd)
+	if len(hours) == 0 {
+		if day.PrecipitationProbabilityMax == nil {
+			return factor("precipitation", cfg.Weights.Precipitation, nil, nil, "降水確率データなし")
+		}
+		s := 1 - math.Min(*day.PrecipitationProbabilityMax, 100)/100
+		return factor("precipitation", cfg.Weights.Precipitation, day.PrecipitationProbabilityMax, &s, "日中を含む1日の最大降水確率で評価")
+	}
+	var maxProb float64
+	for _, h := range hours {
+		maxProb = math.Max(maxProb, h.PrecipitationProbability)
+	}
+	s := 1 - math.Min(maxProb, 100)/100
+	return factor("precipitation", cfg.Weights.Precipitation, &maxProb, &s, "")
+}
+
+// nightHours は詳細データのうち夜の時間帯に含まれる時間別の気象データを返す
+// night.Summarizeと同じく終了時刻ちょうどの時間は含めない
+func nightHours(detail *model.DetailData, nightStart, nightEnd time.Time) []model.HourlyWeather {
+	if detail == nil {
+		return nil
+	}
+	var hours []model.HourlyWeather
+	for _, h := range detail.Weather {
+		if !h.Time.Before(nightStart) && h.Time.Before(nightEnd) {
+			hours = append(hours, h)
+		}
+	}
+	return hours
+}
+
+func factor(name string, weight float64, value, score *float64, note string) model.IndexFactor {
+	f := model.IndexFactor{Name: name, Value: value, Weight: weight, Note: note}
+	if score != nil {
+		s := round1(*score*100) / 100
+		f.Score = &s
+	}
+	return f
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
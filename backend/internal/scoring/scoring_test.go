@@ -0,0 +1,142 @@
+// backend/internal/scoring/scoring_test.go
+package scoring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/night"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+// TestScoreWithoutDetail は詳細データがない場合に予測データの値だけで指数を算出し
+// データのない潮の要因を除外して按分することをテストする
+func TestScoreWithoutDetail(t *testing.T) {
+	cfg := DefaultConfig()
+	date := time.Date(2026, 4, 10, 0, 0, 0, 0, jst)
+	day := &predictionDay{
+		Date:                        "2026-04-10",
+		PredictedAmount:             1.4,
+		MoonAge:                     floatPtr(0),
+		PrecipitationProbabilityMax: floatPtr(0),
+		// 北向きの浜に陸から吹く南風
+		DominantWindDirection: floatPtr(180),
+	}
+	index := score(cfg, date, day, nil, 0)
+
+	if index.Index != 100 || index.Level != 5 {
+		t.Errorf("Index, Level = %d, %d, want 100, 5", index.Index, index.Level)
+	}
+	for _, f := range index.Factors {
+		if (f.Name == "tide") != (f.Score == nil) {
+			t.Errorf("%sの評価の有無が不正です: %v", f.Name, f.Score)
+		}
+	}
+}
+
+// TestScoreWithDetail は詳細データの夜間の風・降水・満潮が評価に使われることをテストする
+func TestScoreWithDetail(t *testing.T) {
+	cfg := DefaultConfig()
+	date := time.Date(2026, 4, 10, 0, 0, 0, 0, jst)
+	day := &predictionDay{Date: "2026-04-10", PredictedAmount: 0.7}
+	detail := &model.DetailData{
+		Tide: model.TideDay{
+			TideName: "大潮",
+			Moon:     model.MoonInfo{Age: floatPtr(14.8)},
+			Events:   []model.TideEvent{{Type: model.TideFlood, Time: time.Date(2026, 4, 10, 23, 10, 0, 0, jst)}},
+		},
+	}
+	for h := 0; h < 48; h++ {
+		hour := model.HourlyWeather{Time: date.Add(time.Duration(h) * time.Hour), WindSpeed: 1, WindDirection: 180}
+		// 日中と、夜の終了時刻（翌5時）ちょうどの雨は夜の評価に含めない
+		if h < 18 || h == 24+5 {
+			hour.PrecipitationProbability = 90
+		}
+		detail.Weather = append(detail.Weather, hour)
+	}
+	index := score(cfg, date, day, detail, 0)
+
+	want := map[string]float64{"prediction": 0.5, "moon": 0, "tide": 1, "wind": 1, "precipitation": 1}
+	for _, f := range index.Factors {
+		if f.Score == nil {
+			t.Fatalf("%sの評価がありません", f.Name)
+		}
+		if diff := *f.Score - want[f.Name]; diff > 0.01 || diff < -0.01 {
+			t.Errorf("%sの評価 = %v, want %v", f.Name, *f.Score, want[f.Name])
+		}
+	}
+
+	if off := score(cfg, time.Date(2026, 8, 1, 0, 0, 0, 0, jst), day, nil, 0); off.InSeason || off.Level != -1 {
+		t.Errorf("シーズン外の判定が不正です: %v, %d", off.InSeason, off.Level)
+	}
+}
+
+// TestScoreWindByShoreBearing は同じ風でも浜の向きによって風の評価が変わることをテストする
+func TestScoreWindByShoreBearing(t *testing.T) {
+	cfg := DefaultConfig()
+	date := time.Date(2026, 4, 10, 0, 0, 0, 0, jst)
+	day := &predictionDay{Date: "2026-04-10", PredictedAmount: 0.7}
+	detail := &model.DetailData{}
+	for h := 0; h < 48; h++ {
+		// 北西から5m/sの風
+		detail.Weather = append(detail.Weather, model.HourlyWeather{Time: date.Add(time.Duration(h) * time.Hour), WindSpeed: 5, WindDirection: 315})
+	}
+
+	windScore := func(spotID string) float64 {
+		s, _ := spot.Get(spotID)
+		for _, f := range score(cfg, date, day, detail, s.ShoreBearing).Factors {
+			if f.Name == "wind" && f.Score != nil {
+				return *f.Score
+			}
+		}
+		t.Fatal("風の評価がありません")
+		return 0
+	}
+	// 北西向きの魚津では海からの風、北向きの富山新港では横風になる
+	uozu, shinko := windScore("uozu"), windScore("toyamashinko")
+	if uozu != 0.35 || shinko != 0.425 {
+		t.Errorf("風の評価 = %v（魚津）, %v（富山新港）, want 0.35, 0.425", uozu, shinko)
+	}
+}
+
+// TestLoadConfig は設定ファイルの値が組み込みの設定に上書きされ、不正な値がエラーになることをテストする
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index.json")
+	os.WriteFile(path, []byte(`{"weights": {"moon": 0.4}}`), 0o644)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗: %v", err)
+	}
+	if cfg.Weights.Moon != 0.4 || cfg.Weights.Prediction != DefaultConfig().Weights.Prediction {
+		t.Errorf("設定の上書きが不正です: %+v", cfg.Weights)
+	}
+
+	// 風速の閾値は時間ごとの評価にも使う
+	os.WriteFile(path, []byte(`{"wind": {"calm_speed": 3, "max_speed": 10}}`), 0o644)
+	if cfg, err := LoadConfig(path); err != nil || cfg.TimelineWind() != (night.WindSpeeds{Calm: 3, Strong: 10}) {
+		t.Errorf("時間ごとの評価の風速の閾値が不正です: %+v, %v", cfg.TimelineWind(), err)
+	}
+
+	os.WriteFile(path, []byte(`{"level_thresholds": [20, 35, 50, 65, 80]}`), 0o644)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("昇順の閾値がエラーになりませんでした")
+	}
+
+	// 夜の時間帯はNIGHT_WINDOWから設定するので、指数設定ファイルでは指定できない
+	os.WriteFile(path, []byte(`{"night_start_hour": 21}`), 0o644)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("night_start_hourがエラーになりませんでした")
+	}
+
+	// 時間ごとの評価には予測値の重みを使わない
+	os.WriteFile(path, []byte(`{"weights": {"prediction": 1, "moon": 0, "tide": 0, "wind": 0, "precipitation": 0}}`), 0o644)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("時間ごとの評価の重みがすべて0の設定がエラーになりませんでした")
+	}
+}
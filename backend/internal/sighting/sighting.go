@@ -0,0 +1,71 @@
+// backend/internal/sighting/sighting.go
+package sighting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// Quantities は量の段階（少ない順）。sightingsテーブルのCHECK制約と揃える
+var Quantities = []string{"なし", "ちらほら", "そこそこ", "爆湧き"}
+
+// CrowdLevels は混雑具合の段階（空いている順）
+var CrowdLevels = []string{"空いている", "普通", "混雑"}
+
+// 観察した時刻として受け付ける範囲
+const (
+	maxAge       = 24 * time.Hour   // 夜に見たものを翌朝に投稿できるよう1日前まで
+	maxClockSkew = 10 * time.Minute // 端末の時計のずれ
+)
+
+// QuantityRank は量の段階を0（なし）〜3（爆湧き）で返す。不明な値は-1
+func QuantityRank(quantity string) int {
+	return indexOf(Quantities, quantity)
+}
+
+// Validate は目撃報告の登録リクエストを検証し、利用者に見せるエラーを返す
+// 地点IDと量・混雑具合の前後の空白は取り除く
+func Validate(req *model.CreateSightingRequest, now time.Time) error {
+	req.SpotID = strings.TrimSpace(req.SpotID)
+	req.Quantity = strings.TrimSpace(req.Quantity)
+	if _, ok := spot.Get(req.SpotID); !ok {
+		return fmt.Errorf("不正な地点です")
+	}
+	if req.ObservedAt.IsZero() {
+		return fmt.Errorf("観察した時刻を入力してください")
+	}
+	if req.ObservedAt.After(now.Add(maxClockSkew)) {
+		return fmt.Errorf("観察した時刻が未来になっています")
+	}
+	if req.ObservedAt.Before(now.Add(-maxAge)) {
+		return fmt.Errorf("観察した時刻は24時間以内にしてください")
+	}
+	if QuantityRank(req.Quantity) < 0 {
+		return fmt.Errorf("量は%sのいずれかを選択してください", strings.Join(Quantities, "・"))
+	}
+	if req.Crowd != nil {
+		crowd := strings.TrimSpace(*req.Crowd)
+		switch {
+		case crowd == "":
+			req.Crowd = nil
+		case indexOf(CrowdLevels, crowd) < 0:
+			return fmt.Errorf("混雑具合は%sのいずれかを選択してください", strings.Join(CrowdLevels, "・"))
+		default:
+			req.Crowd = &crowd
+		}
+	}
+	return nil
+}
+
+func indexOf(values []string, v string) int {
+	for i, value := range values {
+		if value == v {
+			return i
+		}
+	}
+	return -1
+}
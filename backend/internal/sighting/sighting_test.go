@@ -0,0 +1,60 @@
+// backend/internal/sighting/sighting_test.go
+package sighting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Date(2026, 4, 10, 2, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	valid := func() model.CreateSightingRequest {
+		return model.CreateSightingRequest{SpotID: spot.DefaultID, ObservedAt: now.Add(-time.Hour), Quantity: "そこそこ"}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*model.CreateSightingRequest)
+		ok     bool
+	}{
+		{"正常", func(*model.CreateSightingRequest) {}, true},
+		{"混雑具合あり", func(r *model.CreateSightingRequest) { r.Crowd = str("混雑") }, true},
+		{"時計のずれ", func(r *model.CreateSightingRequest) { r.ObservedAt = now.Add(5 * time.Minute) }, true},
+		{"不明な地点", func(r *model.CreateSightingRequest) { r.SpotID = "tokyo" }, false},
+		{"時刻なし", func(r *model.CreateSightingRequest) { r.ObservedAt = time.Time{} }, false},
+		{"未来の時刻", func(r *model.CreateSightingRequest) { r.ObservedAt = now.Add(time.Hour) }, false},
+		{"古すぎる時刻", func(r *model.CreateSightingRequest) { r.ObservedAt = now.Add(-25 * time.Hour) }, false},
+		{"段階にない量", func(r *model.CreateSightingRequest) { r.Quantity = "大漁" }, false},
+		{"段階にない混雑具合", func(r *model.CreateSightingRequest) { r.Crowd = str("ぎゅうぎゅう") }, false},
+	}
+	for _, tt := range tests {
+		req := valid()
+		tt.modify(&req)
+		if err := Validate(&req, now); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+// TestValidateNormalizes は前後の空白を取り除き、空の混雑具合を未入力として扱うことをテストする
+func TestValidateNormalizes(t *testing.T) {
+	now := time.Now()
+	crowd := " "
+	req := model.CreateSightingRequest{SpotID: " " + spot.DefaultID, ObservedAt: now, Quantity: "爆湧き ", Crowd: &crowd}
+	if err := Validate(&req, now); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if req.SpotID != spot.DefaultID || req.Quantity != "爆湧き" || req.Crowd != nil {
+		t.Errorf("req = %+v", req)
+	}
+}
+
+func TestQuantityRank(t *testing.T) {
+	if QuantityRank("なし") != 0 || QuantityRank("爆湧き") != 3 || QuantityRank("") != -1 {
+		t.Error("量の段階の順序が不正です")
+	}
+}
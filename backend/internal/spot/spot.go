@@ -0,0 +1,55 @@
+// backend/internal/spot/spot.go
+package spot
+
+// Spot はホタルイカを掬いに行く浜（観測地点）
+type Spot struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// 浜から海を向いた方角（度、北=0で時計回り）。風向がこれに近いと海から吹く向かい風になる
+	ShoreBearing float64 `json:"shore_bearing"`
+}
+
+// DefaultID は地点が指定されなかった場合に使う浜
+// 従来ハードコードしていた座標（36.76, 137.24）に対応する
+const DefaultID = "iwasehama"
+
+// 潮汐は地点ごとではなく富山湾全体で1つ、tide736.netの富山港（pc=16, hc=3。応答のharbor_namejが「富山」であることを確認済み）のものを使う
+// 富山湾は日本海側で潮位差が30cm程度と小さく、湾内の浜どうしでは満干の時刻も数分しか違わないため、
+// 岩瀬浜から魚津・富山新港までの約30kmの浜はすべて同じ潮汐になる（/api/spotsの地点に潮汐の観測点は含めない）
+const (
+	TidePrefCode   = 16
+	TideHarborCode = 3
+)
+
+var registry = []Spot{
+	{ID: "iwasehama", Name: "岩瀬浜", Latitude: 36.76, Longitude: 137.24, ShoreBearing: 0},
+	{ID: "mizuhashi", Name: "水橋", Latitude: 36.757, Longitude: 137.300, ShoreBearing: 350},
+	{ID: "namerikawa", Name: "滑川", Latitude: 36.771, Longitude: 137.340, ShoreBearing: 330},
+	{ID: "uozu", Name: "魚津", Latitude: 36.823, Longitude: 137.400, ShoreBearing: 315},
+	{ID: "toyamashinko", Name: "富山新港", Latitude: 36.783, Longitude: 137.100, ShoreBearing: 10},
+}
+
+// All は登録されている全地点を返す
+func All() []Spot {
+	spots := make([]Spot, len(registry))
+	copy(spots, registry)
+	return spots
+}
+
+// Get はIDに対応する地点を返す
+func Get(id string) (Spot, bool) {
+	for _, s := range registry {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Spot{}, false
+}
+
+// Default は既定の地点を返す
+func Default() Spot {
+	s, _ := Get(DefaultID)
+	return s
+}
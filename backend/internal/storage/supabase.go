@@ -8,24 +8,46 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 )
 
-// UploadFileToSupabase はSupabase Storageにファイルをアップロードする
-func UploadFileToSupabase(logger *slog.Logger, bucketName, fileName string, fileData []byte, mimeType string) error {
-	url := fmt.Sprintf("%s/storage/v1/object/%s/%s", os.Getenv("SUPABASE_URL"), bucketName, fileName)
-	key := os.Getenv("SUPABASE_SERVICE_KEY")
+// Supabase はSupabase Storageのクライアント
+type Supabase struct {
+	baseURL    string // プロジェクトのURL（例: https://xxxx.supabase.co）
+	serviceKey string
+	client     *http.Client
+	logger     *slog.Logger
+}
+
+// NewSupabase は新しいSupabaseを初期化する
+// URLとサービスキーの検証は設定の読み込み時に行う
+func NewSupabase(baseURL, serviceKey string, logger *slog.Logger) *Supabase {
+	return &Supabase{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		serviceKey: serviceKey,
+		client:     &http.Client{Timeout: time.Second * 10},
+		logger:     logger,
+	}
+}
+
+// PublicURL は公開バケットに置いたファイルのURLを返す
+func (s *Supabase) PublicURL(bucketName, fileName string) string {
+	return fmt.Sprintf("%s/storage/v1/object/public/%s/%s", s.baseURL, bucketName, fileName)
+}
+
+// Upload はSupabase Storageにファイルをアップロードする
+func (s *Supabase) Upload(bucketName, fileName string, fileData []byte, mimeType string) error {
+	url := fmt.Sprintf("%s/storage/v1/object/%s/%s", s.baseURL, bucketName, fileName)
 	req, err := http.NewRequest("POST", url, bytes.NewReader(fileData))
 	if err != nil {
 		return fmt.Errorf("リクエスト作成失敗: %w", err)
 	}
-	req.Header.Set("apikey", key)
-	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("apikey", s.serviceKey)
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
 	req.Header.Set("Content-Type", mimeType)
 	req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
-	client := &http.Client{Timeout: time.Second * 10}
-	resp, err := client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("リクエスト実行失敗: %w", err)
 	}
@@ -34,14 +56,13 @@ func UploadFileToSupabase(logger *slog.Logger, bucketName, fileName string, file
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("アップロード失敗: status=%s, body=%s", resp.Status, string(body))
 	}
-	logger.Info("Supabaseへのファイルアップロード成功", "filename", fileName)
+	s.logger.Info("Supabaseへのファイルアップロード成功", "filename", fileName)
 	return nil
 }
 
-// DeleteFileFromSupabase はSupabase Storageからファイルを削除する
-func DeleteFileFromSupabase(logger *slog.Logger, bucketName string, fileNames []string) error {
-	url := fmt.Sprintf("%s/storage/v1/object/%s", os.Getenv("SUPABASE_URL"), bucketName)
-	key := os.Getenv("SUPABASE_SERVICE_KEY")
+// Delete はSupabase Storageからファイルを削除する
+func (s *Supabase) Delete(bucketName string, fileNames []string) error {
+	url := fmt.Sprintf("%s/storage/v1/object/%s", s.baseURL, bucketName)
 	bodyJSON, err := json.Marshal(map[string][]string{"prefixes": fileNames})
 	if err != nil {
 		return fmt.Errorf("JSONボディ作成失敗: %w", err)
@@ -50,11 +71,10 @@ func DeleteFileFromSupabase(logger *slog.Logger, bucketName string, fileNames []
 	if err != nil {
 		return fmt.Errorf("リクエスト作成失敗: %w", err)
 	}
-	req.Header.Set("apikey", key)
-	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("apikey", s.serviceKey)
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
 	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{Timeout: time.Second * 10}
-	resp, err := client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("リクエスト実行失敗: %w", err)
 	}
@@ -63,6 +83,6 @@ func DeleteFileFromSupabase(logger *slog.Logger, bucketName string, fileNames []
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("ファイル削除失敗: status=%s, body=%s", resp.Status, string(body))
 	}
-	logger.Info("Supabaseからのファイル削除成功", "filenames", fileNames)
+	s.logger.Info("Supabaseからのファイル削除成功", "filenames", fileNames)
 	return nil
 }
\ No newline at end of file
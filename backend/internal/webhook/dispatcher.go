@@ -0,0 +1,234 @@
+// backend/internal/webhook/dispatcher.go
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/scoring"
+)
+
+// 送信に失敗したときの再試行回数（最初の1回を除く）
+const maxRetries = 4
+
+// 送信結果
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// Dispatcher はイベントを登録済みのWebhookに送る
+// 送信は署名付きで行い、失敗した場合は指数バックオフで再試行して結果をPostgresに記録する
+type Dispatcher struct {
+	store      *Store
+	scoring    *scoring.Calculator
+	siteURL    string
+	logger     *slog.Logger
+	client     *http.Client
+	newBackOff func() backoff.BackOff
+	now        func() time.Time
+
+	mu sync.Mutex // 予測データの更新ごとの処理を順番に行う
+	wg sync.WaitGroup
+}
+
+// NewDispatcher は新しいDispatcherを初期化する
+func NewDispatcher(store *Store, scoring *scoring.Calculator, siteURL string, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:      store,
+		scoring:    scoring,
+		siteURL:    siteURL,
+		logger:     logger,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		newBackOff: func() backoff.BackOff { return backoff.NewExponentialBackOff() },
+		now:        time.Now,
+	}
+}
+
+// Dispatch はイベントを購読している有効なWebhookにバックグラウンドで送る
+func (d *Dispatcher) Dispatch(event Event) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		hooks, err := d.store.List()
+		if err != nil {
+			d.logger.Error("Webhook一覧の取得エラー", "error", err)
+			return
+		}
+		for _, hook := range hooks {
+			if hook.Enabled && slices.Contains(hook.Events, event.Type) {
+				d.Send(hook, event)
+			}
+		}
+	}()
+}
+
+// Send はイベントを1つのWebhookに送り、結果を記録して返す
+func (d *Dispatcher) Send(hook model.Webhook, event Event) model.WebhookDelivery {
+	return d.record(hook, event, d.deliver(hook, event, maxRetries))
+}
+
+// SendOnce は再試行せずに1回だけ送り、結果を記録して返す（管理画面からの送信テスト用）
+func (d *Dispatcher) SendOnce(hook model.Webhook, event Event) model.WebhookDelivery {
+	return d.record(hook, event, d.deliver(hook, event, 0))
+}
+
+// record は送信結果を記録する
+func (d *Dispatcher) record(hook model.Webhook, event Event, delivery model.WebhookDelivery) model.WebhookDelivery {
+	if delivery.Status == StatusFailed {
+		d.logger.Warn("Webhookの送信に失敗しました", "webhook_id", hook.ID, "event", event.Type, "attempts", delivery.Attempts, "error", *delivery.Error)
+	}
+	if err := d.store.LogDelivery(delivery); err != nil {
+		d.logger.Error("Webhook送信記録の保存エラー", "error", err)
+	}
+	return delivery
+}
+
+// Wait は送信中のイベントが終わるまで待つ
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// deliver はイベントを送信形式に変換して送り、失敗した場合はretries回まで再試行する
+// 接続エラー・429・5xxは再試行し、それ以外の4xxは再試行しない
+func (d *Dispatcher) deliver(hook model.Webhook, event Event, retries uint64) model.WebhookDelivery {
+	start := d.now()
+	delivery := model.WebhookDelivery{WebhookID: hook.ID, Event: event.Type, Status: StatusFailed, CreatedAt: start}
+	fail := func(err error) model.WebhookDelivery {
+		msg := err.Error()
+		delivery.Error = &msg
+		delivery.DurationMs = d.now().Sub(start).Milliseconds()
+		return delivery
+	}
+
+	body, contentType, err := encode(hook.Format, event)
+	if err != nil {
+		return fail(err)
+	}
+	operation := func() error {
+		delivery.Attempts++
+		req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("リクエストの作成に失敗しました: %w", err))
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("User-Agent", "bakuwaki-yoho-webhook/1.0")
+		req.Header.Set("X-Webhook-Event", event.Type)
+		req.Header.Set("X-Webhook-Signature", Sign(hook.Secret, d.now(), body))
+		if hook.AuthToken != "" {
+			req.Header.Set("Authorization", "Bearer "+hook.AuthToken)
+		}
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return err
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		code := resp.StatusCode
+		delivery.ResponseStatus = &code
+		if code >= 200 && code < 300 {
+			return nil
+		}
+		err = fmt.Errorf("送信先がエラーを返しました (status %d)", code)
+		if code == http.StatusTooManyRequests || code >= 500 {
+			return err
+		}
+		return backoff.Permanent(err)
+	}
+	if err := backoff.Retry(operation, backoff.WithMaxRetries(d.newBackOff(), retries)); err != nil {
+		var permanent *backoff.PermanentError
+		if errors.As(err, &permanent) {
+			err = permanent.Err
+		}
+		return fail(err)
+	}
+	delivery.Status = StatusSucceeded
+	delivery.DurationMs = d.now().Sub(start).Milliseconds()
+	return delivery
+}
+
+// OnPrediction は予測データの更新を受けて、予測の変化と爆湧き指数のイベントを送る
+// CacheManagerのPredictionListenerとして登録して使う
+func (d *Dispatcher) OnPrediction(update cache.PredictionUpdate) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		// 前回との差分はCacheManagerが求めている（初回取得時や内容が変わらなかった場合はnil）
+		if update.Diff != nil {
+			if changes := cache.MaterialDays(update.Diff); len(changes) > 0 {
+				forecast, err := cache.ForecastDays(update.Data)
+				if err != nil {
+					d.logger.Error("Webhook用の予測データの解析エラー", "error", err)
+				}
+				d.Dispatch(PredictionChangeEvent(update.Diff, changes, forecast, d.siteURL))
+			}
+		}
+		d.checkIndex(update.Data)
+	}()
+}
+
+// checkIndex は今日以降の各日の爆湧き指数を求め、閾値を初めて超えた日を通知する
+// 同じ日を二重に送らないよう送信前に記録し、送信に失敗した場合は次回の更新で送り直せるよう記録を取り消す
+func (d *Dispatcher) checkIndex(payload []byte) {
+	var days []struct {
+		Date string `json:"date"`
+	}
+	if err := json.Unmarshal(payload, &days); err != nil {
+		d.logger.Error("Webhook用の予測データの解析エラー", "error", err)
+		return
+	}
+	hooks, err := d.store.List()
+	if err != nil {
+		d.logger.Error("Webhook一覧の取得エラー", "error", err)
+		return
+	}
+	today := d.now().In(jst).Format("2006-01-02")
+	for _, hook := range hooks {
+		if !hook.Enabled || !slices.Contains(hook.Events, EventIndexAbove) {
+			continue
+		}
+		for _, day := range days {
+			if day.Date < today {
+				continue
+			}
+			index, err := d.scoring.Compute(hook.SpotID, day.Date)
+			if err != nil {
+				d.logger.Warn("Webhook用の爆湧き指数の算出エラー", "date", day.Date, "spot", hook.SpotID, "error", err)
+				continue
+			}
+			if !index.InSeason || index.Index < hook.IndexThreshold {
+				continue
+			}
+			claimed, err := d.store.ClaimIndexAlert(hook.ID, day.Date, index.Index)
+			if err != nil {
+				d.logger.Error("指数通知の記録エラー", "webhook_id", hook.ID, "error", err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			delivery := d.Send(hook, IndexAboveEvent(index, hook.IndexThreshold, d.siteURL))
+			if delivery.Status == StatusFailed {
+				if err := d.store.ReleaseIndexAlert(hook.ID, day.Date); err != nil {
+					d.logger.Error("指数通知の記録の取り消しエラー", "webhook_id", hook.ID, "error", err)
+				}
+			}
+		}
+	}
+}
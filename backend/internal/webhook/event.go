@@ -0,0 +1,135 @@
+// backend/internal/webhook/event.go
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/cache"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/level"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/spot"
+)
+
+// イベントの種類
+const (
+	EventPredictionUpdated = "prediction.updated" // 7日間の予測が大きく変わった
+	EventIndexAbove        = "index.above"        // 爆湧き指数が閾値を超えた
+	EventFieldReport       = "post.field_report"  // 現地情報の投稿があった
+	EventPinnedPost        = "post.pinned"        // 投稿が固定された
+	EventTest              = "test"               // 管理画面からの送信テスト（登録時の絞り込みに関係なく送る）
+)
+
+// Events は登録時に指定できるイベントの一覧
+var Events = []string{EventPredictionUpdated, EventIndexAbove, EventFieldReport, EventPinnedPost}
+
+// Event は送信するイベント
+// Title・Text・URLはDiscordなどのチャット向けの表示に、Dataは汎用JSON形式で使う
+type Event struct {
+	Type       string
+	OccurredAt time.Time
+	Title      string
+	Text       string
+	URL        string
+	Data       interface{}
+}
+
+// PredictionChangeEvent は予測の大きな変化のイベントを作る
+// changesはcache.MaterialDaysで選んだ日、forecastは更新後の予測全体
+func PredictionChangeEvent(diff *model.PredictionDiff, changes []model.PredictionDayDiff, forecast []cache.ForecastDay, siteURL string) Event {
+	type change struct {
+		Date          string   `json:"date"`
+		Previous      *float64 `json:"previous_amount"`
+		Current       *float64 `json:"predicted_amount"`
+		PreviousLevel *int     `json:"previous_level"`
+		Level         *int     `json:"level"`
+	}
+	type day struct {
+		Date    string  `json:"date"`
+		Current float64 `json:"predicted_amount"`
+		Level   int     `json:"level"`
+	}
+
+	var lines []string
+	changed := []change{}
+	for _, d := range changes {
+		changed = append(changed, change{Date: d.Date, Previous: d.PreviousAmount, Current: d.CurrentAmount, PreviousLevel: d.PreviousLevel, Level: d.Level})
+		if d.PreviousAmount == nil {
+			lines = append(lines, fmt.Sprintf("%s %s（%.2f）", level.FormatDate(d.Date), level.Name(*d.Level), *d.CurrentAmount))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s %s → %s（%.2f → %.2f）", level.FormatDate(d.Date), level.Name(*d.PreviousLevel), level.Name(*d.Level), *d.PreviousAmount, *d.CurrentAmount))
+		}
+	}
+	days := []day{}
+	for _, d := range forecast {
+		days = append(days, day{Date: d.Date, Current: d.Amount, Level: d.Level})
+	}
+	return Event{
+		Type:       EventPredictionUpdated,
+		OccurredAt: diff.ToFetchedAt,
+		Title:      "爆湧き予報が更新されました",
+		Text:       strings.Join(lines, "\n"),
+		URL:        strings.TrimSuffix(siteURL, "/") + "/",
+		Data: map[string]interface{}{
+			"fetched_at": diff.ToFetchedAt,
+			"hash":       diff.ToHash,
+			"changes":    changed,
+			"forecast":   days,
+		},
+	}
+}
+
+// IndexAboveEvent は爆湧き指数が閾値を超えたイベントを作る
+func IndexAboveEvent(index *model.BakuwakiIndex, threshold int, siteURL string) Event {
+	name := index.SpotID
+	if s, ok := spot.Get(index.SpotID); ok {
+		name = s.Name
+	}
+	url := strings.TrimSuffix(siteURL, "/") + "/detail/" + index.Date
+	if index.SpotID != spot.DefaultID {
+		url += "?spot=" + index.SpotID
+	}
+	return Event{
+		Type:       EventIndexAbove,
+		OccurredAt: time.Now(),
+		Title:      fmt.Sprintf("%s %sの爆湧き指数が%dになりました", level.FormatDate(index.Date), name, index.Index),
+		Text:       fmt.Sprintf("%s（閾値%d）", level.Name(index.Level), threshold),
+		URL:        url,
+		Data: map[string]interface{}{
+			"threshold": threshold,
+			"index":     index,
+		},
+	}
+}
+
+// PostEvent は投稿のイベント（現地情報の投稿・投稿の固定）を作る
+func PostEvent(eventType string, post model.Post, siteURL string) Event {
+	title := "現地情報の投稿がありました"
+	if eventType == EventPinnedPost {
+		title = "投稿が固定されました"
+	}
+	post.DeviceID = nil
+	return Event{
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Title:      title,
+		Text:       post.Username + ": " + post.Content,
+		URL:        strings.TrimSuffix(siteURL, "/") + "/",
+		Data: map[string]interface{}{
+			"post": post,
+		},
+	}
+}
+
+// TestEvent は送信テスト用のイベントを作る
+func TestEvent(siteURL string) Event {
+	return Event{
+		Type:       EventTest,
+		OccurredAt: time.Now(),
+		Title:      "Webhookの送信テスト",
+		Text:       "このメッセージが届いていれば設定は正しく行われています",
+		URL:        strings.TrimSuffix(siteURL, "/") + "/",
+		Data:       map[string]interface{}{},
+	}
+}
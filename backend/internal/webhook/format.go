@@ -0,0 +1,97 @@
+// backend/internal/webhook/format.go
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 送信形式
+const (
+	FormatJSON    = "json"
+	FormatDiscord = "discord"
+	FormatSlack   = "slack"
+	FormatLINE    = "line" // LINE Notify形式（message=... のフォーム送信）
+)
+
+// Formats は登録時に指定できる送信形式の一覧
+var Formats = []string{FormatJSON, FormatDiscord, FormatSlack, FormatLINE}
+
+// Discordの埋め込みの色（ホタルイカの青）
+const discordColor = 0x2f80ed
+
+// encode はイベントを送信形式に合わせた本文とContent-Typeにする
+func encode(format string, event Event) ([]byte, string, error) {
+	switch format {
+	case FormatJSON:
+		body, err := json.Marshal(map[string]interface{}{
+			"event":       event.Type,
+			"occurred_at": event.OccurredAt,
+			"title":       event.Title,
+			"text":        event.Text,
+			"url":         event.URL,
+			"data":        event.Data,
+		})
+		return body, "application/json", err
+	case FormatDiscord:
+		body, err := json.Marshal(map[string]interface{}{
+			"embeds": []map[string]interface{}{{
+				"title":       event.Title,
+				"description": event.Text,
+				"url":         event.URL,
+				"color":       discordColor,
+				"timestamp":   event.OccurredAt.UTC().Format(time.RFC3339),
+			}},
+		})
+		return body, "application/json", err
+	case FormatSlack:
+		text := fmt.Sprintf("*<%s|%s>*", event.URL, slackEscape(event.Title))
+		if event.Text != "" {
+			text += "\n" + slackEscape(event.Text)
+		}
+		body, err := json.Marshal(map[string]interface{}{"text": text})
+		return body, "application/json", err
+	case FormatLINE:
+		message := "\n" + event.Title
+		if event.Text != "" {
+			message += "\n" + event.Text
+		}
+		message += "\n" + event.URL
+		return []byte(url.Values{"message": {message}}.Encode()), "application/x-www-form-urlencoded", nil
+	}
+	return nil, "", fmt.Errorf("未対応の送信形式です: %s", format)
+}
+
+// slackEscape はSlackのmrkdwnで特別な意味を持つ文字をエスケープする
+func slackEscape(s string) string {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch r {
+		case '&':
+			out = append(out, []rune("&amp;")...)
+		case '<':
+			out = append(out, []rune("&lt;")...)
+		case '>':
+			out = append(out, []rune("&gt;")...)
+		default:
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+// Sign は受信側で検証するための署名ヘッダーの値を作る
+// 値は "t=<UNIX時刻>,v1=<HMAC-SHA256(secret, "<UNIX時刻>.<本文>")の16進数>"
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
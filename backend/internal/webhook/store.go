@@ -0,0 +1,187 @@
+// backend/internal/webhook/store.go
+package webhook
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+// Store はWebhookの登録内容と送信記録をPostgresに保存・参照する
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore は新しいStoreを初期化する
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+const webhookColumns = `id, name, url, format, events, secret, COALESCE(auth_token, ''), index_threshold, spot_id, enabled, created_at, updated_at`
+
+// List は登録されている全てのWebhookを返す
+func (s *Store) List() ([]model.Webhook, error) {
+	rows, err := s.db.Query(`SELECT ` + webhookColumns + ` FROM webhooks ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("Webhook一覧の取得失敗: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []model.Webhook{}
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Webhook行のスキャン失敗: %w", err)
+		}
+		hooks = append(hooks, *hook)
+	}
+	return hooks, rows.Err()
+}
+
+// Get は指定したIDのWebhookを返す（存在しない場合はnil）
+func (s *Store) Get(id int) (*model.Webhook, error) {
+	hook, err := scanWebhook(s.db.QueryRow(`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Webhookの取得失敗: %w", err)
+	}
+	return hook, nil
+}
+
+// Create はWebhookを登録し、IDと作成日時を設定する
+func (s *Store) Create(hook *model.Webhook) error {
+	query := `INSERT INTO webhooks (name, url, format, events, secret, auth_token, index_threshold, spot_id, enabled)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+	err := s.db.QueryRow(query, hook.Name, hook.URL, hook.Format, pq.Array(hook.Events), hook.Secret, hook.AuthToken,
+		hook.IndexThreshold, hook.SpotID, hook.Enabled).Scan(&hook.ID, &hook.CreatedAt, &hook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("Webhookの登録失敗: %w", err)
+	}
+	hook.HasAuthToken = hook.AuthToken != ""
+	return nil
+}
+
+// Update はWebhookの登録内容を更新する。更新した場合はtrueを返す
+func (s *Store) Update(hook *model.Webhook) (bool, error) {
+	query := `UPDATE webhooks SET name = $2, url = $3, format = $4, events = $5, auth_token = NULLIF($6, ''),
+			index_threshold = $7, spot_id = $8, enabled = $9, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	result, err := s.db.Exec(query, hook.ID, hook.Name, hook.URL, hook.Format, pq.Array(hook.Events), hook.AuthToken,
+		hook.IndexThreshold, hook.SpotID, hook.Enabled)
+	if err != nil {
+		return false, fmt.Errorf("Webhookの更新失敗: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// UpdateSecret は署名用の鍵を更新する
+func (s *Store) UpdateSecret(id int, secret string) error {
+	if _, err := s.db.Exec(`UPDATE webhooks SET secret = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, secret); err != nil {
+		return fmt.Errorf("署名鍵の更新失敗: %w", err)
+	}
+	return nil
+}
+
+// Delete はWebhookを削除する。削除した場合はtrueを返す
+func (s *Store) Delete(id int) (bool, error) {
+	result, err := s.db.Exec(`DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("Webhookの削除失敗: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// LogDelivery は送信結果を記録する
+func (s *Store) LogDelivery(d model.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event, status, attempts, response_status, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := s.db.Exec(query, d.WebhookID, d.Event, d.Status, d.Attempts, d.ResponseStatus, d.Error, d.DurationMs); err != nil {
+		return fmt.Errorf("送信記録の保存失敗: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries はWebhookの送信記録を新しい順に返す
+func (s *Store) ListDeliveries(webhookID, limit int) ([]model.WebhookDelivery, error) {
+	rows, err := s.db.Query(`SELECT id, webhook_id, event, status, attempts, response_status, error, duration_ms, created_at
+		FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT $2`, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("送信記録の取得失敗: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		var d model.WebhookDelivery
+		var status sql.NullInt64
+		var errMsg sql.NullString
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Status, &d.Attempts, &status, &errMsg, &d.DurationMs, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("送信記録のスキャン失敗: %w", err)
+		}
+		if status.Valid {
+			code := int(status.Int64)
+			d.ResponseStatus = &code
+		}
+		if errMsg.Valid {
+			d.Error = &errMsg.String
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// ClaimIndexAlert は指定日の爆湧き指数の通知を記録する
+// すでに通知済みの場合はfalseを返す
+func (s *Store) ClaimIndexAlert(webhookID int, date string, index int) (bool, error) {
+	result, err := s.db.Exec(`INSERT INTO webhook_index_alerts (webhook_id, target_date, index_value)
+		VALUES ($1, $2, $3) ON CONFLICT (webhook_id, target_date) DO NOTHING`, webhookID, date, index)
+	if err != nil {
+		return false, fmt.Errorf("指数通知の記録失敗: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ReleaseIndexAlert は送信に失敗した指数の通知の記録を取り消す
+func (s *Store) ReleaseIndexAlert(webhookID int, date string) error {
+	if _, err := s.db.Exec(`DELETE FROM webhook_index_alerts WHERE webhook_id = $1 AND target_date = $2`, webhookID, date); err != nil {
+		return fmt.Errorf("指数通知の記録の取り消し失敗: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row rowScanner) (*model.Webhook, error) {
+	var hook model.Webhook
+	var events pq.StringArray
+	if err := row.Scan(&hook.ID, &hook.Name, &hook.URL, &hook.Format, &events, &hook.Secret, &hook.AuthToken,
+		&hook.IndexThreshold, &hook.SpotID, &hook.Enabled, &hook.CreatedAt, &hook.UpdatedAt); err != nil {
+		return nil, err
+	}
+	hook.Events = []string(events)
+	if hook.Events == nil {
+		hook.Events = []string{}
+	}
+	hook.HasAuthToken = hook.AuthToken != ""
+	return &hook, nil
+}
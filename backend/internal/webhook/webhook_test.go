@@ -0,0 +1,147 @@
+// backend/internal/webhook/webhook_test.go
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/yuchi1128/hotaruika-bakuwaki-forecast/backend/internal/model"
+)
+
+func newTestDispatcher() *Dispatcher {
+	d := NewDispatcher(nil, nil, "https://example.com", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.newBackOff = func() backoff.BackOff { return &backoff.ZeroBackOff{} }
+	d.now = func() time.Time { return time.Unix(1775955600, 0) }
+	return d
+}
+
+func TestDeliverRetriesAndSigns(t *testing.T) {
+	calls := 0
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	hook := model.Webhook{ID: 1, URL: server.URL, Format: FormatDiscord, Secret: "s3cret", AuthToken: "token"}
+	delivery := newTestDispatcher().deliver(hook, TestEvent("https://example.com"), maxRetries)
+	if delivery.Status != StatusSucceeded || delivery.Attempts != 3 {
+		t.Fatalf("delivery = %+v", delivery)
+	}
+	if delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("ResponseStatus = %v", delivery.ResponseStatus)
+	}
+
+	if got, want := header.Get("X-Webhook-Signature"), Sign("s3cret", time.Unix(1775955600, 0), body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(header.Get("X-Webhook-Signature"), "t=1775955600,v1=") {
+		t.Errorf("signature = %q", header.Get("X-Webhook-Signature"))
+	}
+	if header.Get("Authorization") != "Bearer token" || header.Get("X-Webhook-Event") != EventTest {
+		t.Errorf("header = %v", header)
+	}
+	var discord struct {
+		Embeds []struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+		} `json:"embeds"`
+	}
+	if err := json.Unmarshal(body, &discord); err != nil || len(discord.Embeds) != 1 || discord.Embeds[0].Title != "Webhookの送信テスト" {
+		t.Errorf("body = %s (%v)", body, err)
+	}
+}
+
+func TestDeliverDoesNotRetryClientError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	hook := model.Webhook{URL: server.URL, Format: FormatJSON, Secret: "x"}
+	delivery := newTestDispatcher().deliver(hook, TestEvent("https://example.com"), maxRetries)
+	if delivery.Status != StatusFailed || delivery.Attempts != 1 || calls != 1 {
+		t.Fatalf("delivery = %+v, calls = %d", delivery, calls)
+	}
+	if delivery.Error == nil || !strings.Contains(*delivery.Error, "404") {
+		t.Errorf("Error = %v", delivery.Error)
+	}
+}
+
+func TestDeliverGivesUpAfterRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hook := model.Webhook{URL: server.URL, Format: FormatSlack, Secret: "x"}
+	delivery := newTestDispatcher().deliver(hook, TestEvent("https://example.com"), maxRetries)
+	if delivery.Status != StatusFailed || delivery.Attempts != maxRetries+1 {
+		t.Fatalf("delivery = %+v", delivery)
+	}
+}
+
+// TestDeliverWithoutRetries は送信テストでは5xxでも再試行しないことをテストする
+func TestDeliverWithoutRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hook := model.Webhook{URL: server.URL, Format: FormatJSON, Secret: "x"}
+	delivery := newTestDispatcher().deliver(hook, TestEvent("https://example.com"), 0)
+	if delivery.Status != StatusFailed || delivery.Attempts != 1 || calls != 1 {
+		t.Fatalf("delivery = %+v, calls = %d", delivery, calls)
+	}
+}
+
+func TestEncodeFormats(t *testing.T) {
+	event := Event{Type: EventFieldReport, Title: "現地情報の投稿がありました", Text: "太郎: <岩瀬浜> で湧いてます & 大漁", URL: "https://example.com/"}
+
+	body, contentType, err := encode(FormatSlack, event)
+	if err != nil || contentType != "application/json" {
+		t.Fatalf("slack: %v %s", err, contentType)
+	}
+	var slack struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(body, &slack); err != nil {
+		t.Fatal(err)
+	}
+	if want := "*<https://example.com/|現地情報の投稿がありました>*\n太郎: &lt;岩瀬浜&gt; で湧いてます &amp; 大漁"; slack.Text != want {
+		t.Errorf("slack text = %q, want %q", slack.Text, want)
+	}
+
+	body, contentType, err = encode(FormatLINE, event)
+	if err != nil || contentType != "application/x-www-form-urlencoded" {
+		t.Fatalf("line: %v %s", err, contentType)
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil || !strings.Contains(values.Get("message"), "太郎: <岩瀬浜> で湧いてます & 大漁") {
+		t.Errorf("line body = %s", body)
+	}
+
+	if _, _, err := encode("teams", event); err == nil {
+		t.Error("未対応の形式はエラーになるべき")
+	}
+}
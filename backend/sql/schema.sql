@@ -51,4 +51,102 @@ CREATE TABLE banned_devices (
     device_id TEXT NOT NULL UNIQUE,
     reason TEXT,
     banned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
+
+CREATE TABLE prediction_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    fetched_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    content_hash CHAR(64) NOT NULL,
+    payload JSONB NOT NULL
+);
+
+CREATE INDEX idx_prediction_snapshots_fetched_at ON prediction_snapshots (fetched_at DESC);
+
+CREATE TABLE night_observations (
+    night_date DATE PRIMARY KEY,
+    predicted_amount DOUBLE PRECISION,
+    prediction_fetched_at TIMESTAMP WITH TIME ZONE,
+    report_count INTEGER NOT NULL DEFAULT 0,
+    good_count INTEGER NOT NULL DEFAULT 0,
+    bad_count INTEGER NOT NULL DEFAULT 0,
+    poll_votes INTEGER NOT NULL DEFAULT 0,
+    poll_score DOUBLE PRECISION,
+    text_score DOUBLE PRECISION,
+    observed_score DOUBLE PRECISION,
+    computed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE cache_snapshots (
+    name TEXT PRIMARY KEY,
+    payload BYTEA NOT NULL,
+    saved_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE push_subscriptions (
+    device_id TEXT PRIMARY KEY,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh TEXT NOT NULL,
+    auth TEXT NOT NULL,
+    min_level INTEGER NOT NULL DEFAULT 4,
+    jump_threshold DOUBLE PRECISION NOT NULL DEFAULT 0.3,
+    spots TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE push_notifications (
+    device_id TEXT NOT NULL REFERENCES push_subscriptions(device_id) ON DELETE CASCADE,
+    target_date DATE NOT NULL,
+    predicted_amount DOUBLE PRECISION NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (device_id, target_date)
+);
+
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    format VARCHAR(20) NOT NULL CHECK (format IN ('json', 'discord', 'slack', 'line')),
+    events TEXT[] NOT NULL,
+    secret TEXT NOT NULL,
+    auth_token TEXT,
+    index_threshold INTEGER NOT NULL DEFAULT 70,
+    spot_id TEXT NOT NULL DEFAULT 'iwasehama',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    attempts INTEGER NOT NULL,
+    response_status INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at DESC);
+
+CREATE TABLE webhook_index_alerts (
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    target_date DATE NOT NULL,
+    index_value INTEGER NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (webhook_id, target_date)
+);
+
+CREATE TABLE sightings (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+    spot_id TEXT NOT NULL,
+    observed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    quantity VARCHAR(20) NOT NULL CHECK (quantity IN ('なし', 'ちらほら', 'そこそこ', '爆湧き')),
+    crowd VARCHAR(20) CHECK (crowd IN ('空いている', '普通', '混雑')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sightings_spot_observed_at ON sightings (spot_id, observed_at DESC);
\ No newline at end of file
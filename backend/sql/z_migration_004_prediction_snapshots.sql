@@ -0,0 +1,11 @@
+-- Migration: 予測データのスナップショット履歴
+-- 予測APIから取得したペイロードを更新ごとに保存し、同じ夜の予測がどう変化したかを追えるようにする
+
+CREATE TABLE IF NOT EXISTS prediction_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    fetched_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    content_hash CHAR(64) NOT NULL,
+    payload JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_prediction_snapshots_fetched_at ON prediction_snapshots (fetched_at DESC);
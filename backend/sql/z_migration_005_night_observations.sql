@@ -0,0 +1,16 @@
+-- Migration: 予報精度スコアリング
+-- 夜ごとの「現地情報」投稿の集計結果と、その夜に対する予測値を保存する
+
+CREATE TABLE IF NOT EXISTS night_observations (
+    night_date DATE PRIMARY KEY,
+    predicted_amount DOUBLE PRECISION,
+    prediction_fetched_at TIMESTAMP WITH TIME ZONE,
+    report_count INTEGER NOT NULL DEFAULT 0,
+    good_count INTEGER NOT NULL DEFAULT 0,
+    bad_count INTEGER NOT NULL DEFAULT 0,
+    poll_votes INTEGER NOT NULL DEFAULT 0,
+    poll_score DOUBLE PRECISION,
+    text_score DOUBLE PRECISION,
+    observed_score DOUBLE PRECISION,
+    computed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,8 @@
+-- Migration: キャッシュのスナップショット
+-- コールドスタート直後でも前回取得した予報データを返せるよう、更新のたびにキャッシュ全体を保存する
+
+CREATE TABLE IF NOT EXISTS cache_snapshots (
+    name TEXT PRIMARY KEY,
+    payload JSONB NOT NULL,
+    saved_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
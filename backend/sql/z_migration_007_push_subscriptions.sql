@@ -0,0 +1,23 @@
+-- Migration: Web Push通知の購読
+-- 購読情報はフロントエンドが送るX-Device-IDごとに1件保持し、通知の条件（レベル・急上昇幅・地点）も合わせて保存する
+
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    device_id TEXT PRIMARY KEY,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh TEXT NOT NULL,
+    auth TEXT NOT NULL,
+    min_level INTEGER NOT NULL DEFAULT 4,
+    jump_threshold DOUBLE PRECISION NOT NULL DEFAULT 0.3,
+    spots TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 同じ日付について何度も通知しないよう、最後に通知した予測値を記録する
+CREATE TABLE IF NOT EXISTS push_notifications (
+    device_id TEXT NOT NULL REFERENCES push_subscriptions(device_id) ON DELETE CASCADE,
+    target_date DATE NOT NULL,
+    predicted_amount DOUBLE PRECISION NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (device_id, target_date)
+);
@@ -0,0 +1,40 @@
+-- Migration: Webhook
+-- 管理人が登録したURLに、予報の更新・爆湧き指数・投稿などのイベントを送る。送信結果は webhook_deliveries に記録する
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    format VARCHAR(20) NOT NULL CHECK (format IN ('json', 'discord', 'slack', 'line')),
+    events TEXT[] NOT NULL,
+    secret TEXT NOT NULL,
+    auth_token TEXT,
+    index_threshold INTEGER NOT NULL DEFAULT 70,
+    spot_id TEXT NOT NULL DEFAULT 'iwasehama',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    attempts INTEGER NOT NULL,
+    response_status INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at DESC);
+
+-- 爆湧き指数の通知は同じ日付について1回だけ送る
+CREATE TABLE IF NOT EXISTS webhook_index_alerts (
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    target_date DATE NOT NULL,
+    index_value INTEGER NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (webhook_id, target_date)
+);
@@ -0,0 +1,14 @@
+-- Migration: 現地情報の目撃報告
+-- 「現地情報」の投稿に、場所・観察した時刻・量（なし/ちらほら/そこそこ/爆湧き）・混雑具合を集計できる形で添える
+
+CREATE TABLE IF NOT EXISTS sightings (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+    spot_id TEXT NOT NULL,
+    observed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    quantity VARCHAR(20) NOT NULL CHECK (quantity IN ('なし', 'ちらほら', 'そこそこ', '爆湧き')),
+    crowd VARCHAR(20) CHECK (crowd IN ('空いている', '普通', '混雑')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sightings_spot_observed_at ON sightings (spot_id, observed_at DESC);
@@ -0,0 +1,4 @@
+-- Migration: キャッシュのスナップショットをBYTEAで保存する
+-- JSONBは空白やキーの順序を変えてしまい、復元したデータのハッシュ（ETag）が再起動のたびに変わっていた
+
+ALTER TABLE cache_snapshots ALTER COLUMN payload TYPE BYTEA USING convert_to(payload::text, 'UTF8');